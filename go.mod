@@ -2,6 +2,41 @@ module goserver
 
 go 1.19
 
-require github.com/streadway/amqp v1.0.0
+require (
+	github.com/streadway/amqp v1.0.0
+	github.com/tendermint/tendermint v0.35.9
+	goserver/message v0.0.0
+	server/headerTest v0.0.0
+	server/iavlTree v0.0.0
+	server/verifyValidator v0.0.0
+)
 
-replace goserver/message => ./messageReplace
+require (
+	github.com/btcsuite/btcd v0.22.1 // indirect
+	github.com/confio/ics23/go v0.9.0 // indirect
+	github.com/decred/dcrd/bech32 v1.1.2 // indirect
+	github.com/gogo/protobuf v1.3.2 // indirect
+	github.com/golang/protobuf v1.5.2 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.16 // indirect
+	github.com/mihongtech/tendermint v0.0.0 // indirect
+	github.com/oasisprotocol/curve25519-voi v0.0.0-20210609091139-0a56a4bca00b // indirect
+	github.com/petermattis/goid v0.0.0-20180202154549-b0b1615b78e5 // indirect
+	github.com/rs/zerolog v1.27.0 // indirect
+	github.com/sasha-s/go-deadlock v0.3.1 // indirect
+	golang.org/x/crypto v0.5.0 // indirect
+	golang.org/x/net v0.7.0 // indirect
+	golang.org/x/sys v0.5.0 // indirect
+	golang.org/x/text v0.7.0 // indirect
+	google.golang.org/genproto v0.0.0-20221118155620-16455021b5e6 // indirect
+	google.golang.org/grpc v1.52.0 // indirect
+	google.golang.org/protobuf v1.28.2-0.20220831092852-f930b1dc76e8 // indirect
+)
+
+replace (
+	goserver/message => ./message
+	server/headerTest => ./verifyElementOfHeader
+	server/iavlTree => ./iavl
+	server/verifyValidator => ./verifyValidator
+)
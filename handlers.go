@@ -0,0 +1,1137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	tmmath "github.com/tendermint/tendermint/libs/math"
+	protoTypes "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+
+	"goserver/light"
+	"goserver/message"
+	"goserver/rpc"
+	"goserver/store"
+	"server/headerTest/header"
+	"server/iavlTree/state"
+	"server/iavlTree/txdecode"
+	"server/iavlTree/txproof"
+	"server/verifyValidator/validator"
+)
+
+func init() {
+	// "1" is the original hard-coded vote check the Node side already calls.
+	Register("1", func(json.RawMessage) (interface{}, error) {
+		result, err := message.Message()
+		if err != nil {
+			return nil, err
+		}
+		return struct {
+			Valid bool `json:"valid"`
+			message.VoteResult
+		}{result.Valid(), result}, nil
+	})
+	Register("verify_vote", verifyVote)
+	Register("duplicate_vote_evidence", duplicateVoteEvidence)
+	Register("verify_proposal", verifyProposal)
+	Register("verify_commit", verifyCommit)
+	Register("verify_commit_trusting", verifyCommitTrusting)
+	Register("verify_header_field", verifyHeaderField)
+	Register("verify_header_chain", verifyHeaderChain)
+	Register("verify_rpc_commit", verifyRPCCommit)
+	Register("light_verify", lightVerify)
+	Register("trusted_light_block", trustedLightBlock)
+	Register("prune_trusted", pruneTrusted)
+	Register("validator_set_hash", validatorSetHash)
+	Register("tx_inclusion_proof", txInclusionProof)
+	Register("verify_tx_inclusion", verifyTxInclusion)
+	Register("tx_result_proof", txResultProof)
+	Register("verify_tx_result", verifyTxResult)
+	Register("verify_state", verifyState)
+	Register("verify_contract_state", verifyContractState)
+}
+
+// verifyVoteRequest is the data payload of the verify_vote service. The vote
+// is given either as tendermint RPC JSON or as a base64 protobuf Vote. The key
+// type is ed25519, the default, secp256k1 or sr25519.
+type verifyVoteRequest struct {
+	ChainID   string          `json:"chain_id"`
+	Vote      json.RawMessage `json:"vote,omitempty"`
+	VoteProto []byte          `json:"vote_proto,omitempty"`
+	KeyType   string          `json:"key_type,omitempty"`
+	PubKey    []byte          `json:"pub_key"`
+	Signature []byte          `json:"signature,omitempty"`
+}
+
+func verifyVote(data json.RawMessage) (interface{}, error) {
+	var req verifyVoteRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse verify_vote request: %w", err)
+	}
+
+	vote, err := decodeVote(req.Vote, req.VoteProto)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := message.PubKeyFromBytes(req.KeyType, req.PubKey)
+	if err != nil {
+		return message.VoteResult{Status: message.VoteUnsupportedKeyType, Error: err.Error()}, nil
+	}
+
+	return message.VerifyVote(req.ChainID, vote, pubKey, req.Signature), nil
+}
+
+func decodeVote(voteJSON json.RawMessage, voteProto []byte) (*protoTypes.Vote, error) {
+	switch {
+	case len(voteProto) > 0:
+		vote := new(protoTypes.Vote)
+		if err := vote.Unmarshal(voteProto); err != nil {
+			return nil, fmt.Errorf("%w: vote protobuf: %v", message.ErrBadEncoding, err)
+		}
+		return vote, nil
+	case len(voteJSON) > 0:
+		var vote types.Vote
+		if err := tmjson.Unmarshal(voteJSON, &vote); err != nil {
+			return nil, fmt.Errorf("%w: vote json: %v", message.ErrBadEncoding, err)
+		}
+		return vote.ToProto(), nil
+	default:
+		return nil, errors.New("missing vote")
+	}
+}
+
+// duplicateVoteEvidenceRequest is the data payload of the
+// duplicate_vote_evidence service. The votes are given like in verify_vote,
+// Validators is the validator set at their height, ValidatorsHash, if set,
+// the header's ValidatorsHash that set must hash to, and BlockTime, which is
+// required, the time of the block at that height.
+type duplicateVoteEvidenceRequest struct {
+	ChainID        string           `json:"chain_id"`
+	VoteA          json.RawMessage  `json:"vote_a,omitempty"`
+	VoteAProto     []byte           `json:"vote_a_proto,omitempty"`
+	VoteB          json.RawMessage  `json:"vote_b,omitempty"`
+	VoteBProto     []byte           `json:"vote_b_proto,omitempty"`
+	Validators     json.RawMessage  `json:"validators"`
+	ValidatorsHash tmbytes.HexBytes `json:"validators_hash,omitempty"`
+	BlockTime      time.Time        `json:"block_time"`
+}
+
+type duplicateVoteEvidenceResult struct {
+	Valid    bool                   `json:"valid"`
+	Evidence *message.DuplicateVote `json:"evidence,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+}
+
+func duplicateVoteEvidence(data json.RawMessage) (interface{}, error) {
+	var req duplicateVoteEvidenceRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse duplicate_vote_evidence request: %w", err)
+	}
+
+	voteA, err := decodeVote(req.VoteA, req.VoteAProto)
+	if err != nil {
+		return nil, fmt.Errorf("vote A: %w", err)
+	}
+	voteB, err := decodeVote(req.VoteB, req.VoteBProto)
+	if err != nil {
+		return nil, fmt.Errorf("vote B: %w", err)
+	}
+	var vals []*types.Validator
+	if err := tmjson.Unmarshal(req.Validators, &vals); err != nil {
+		return nil, fmt.Errorf("parse validators: %w", err)
+	}
+	if req.BlockTime.IsZero() {
+		return nil, errors.New("block_time is required")
+	}
+
+	ev, err := message.VerifyDuplicateVote(req.ChainID, voteA, voteB, &types.ValidatorSet{Validators: vals},
+		req.ValidatorsHash, req.BlockTime)
+	switch {
+	case err == nil:
+		return duplicateVoteEvidenceResult{Valid: true, Evidence: ev}, nil
+	case isVerificationFailure(err), errors.Is(err, message.ErrInvalidEvidence), errors.Is(err, message.ErrNotInSet):
+		return duplicateVoteEvidenceResult{Error: err.Error()}, nil
+	default:
+		return nil, err
+	}
+}
+
+// verifyProposalRequest is the data payload of the verify_proposal service.
+// The proposal is given either as tendermint RPC JSON or as a base64 protobuf
+// Proposal, and ValidatorPages holds every page of the /validators response
+// at the proposal's height, which the proposer is derived from.
+type verifyProposalRequest struct {
+	ChainID        string          `json:"chain_id"`
+	Proposal       json.RawMessage `json:"proposal,omitempty"`
+	ProposalProto  []byte          `json:"proposal_proto,omitempty"`
+	ValidatorPages validatorPages  `json:"validator_pages"`
+	Signature      []byte          `json:"signature,omitempty"`
+}
+
+func verifyProposal(data json.RawMessage) (interface{}, error) {
+	var req verifyProposalRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse verify_proposal request: %w", err)
+	}
+
+	proposal := new(protoTypes.Proposal)
+	switch {
+	case len(req.ProposalProto) > 0:
+		if err := proposal.Unmarshal(req.ProposalProto); err != nil {
+			return nil, fmt.Errorf("%w: proposal protobuf: %v", message.ErrBadEncoding, err)
+		}
+	case len(req.Proposal) > 0:
+		var p types.Proposal
+		if err := tmjson.Unmarshal(req.Proposal, &p); err != nil {
+			return nil, fmt.Errorf("%w: proposal json: %v", message.ErrBadEncoding, err)
+		}
+		proposal = p.ToProto()
+	default:
+		return nil, errors.New("missing proposal")
+	}
+
+	vals, err := rpc.ParseValidatorSet(req.ValidatorPages.bytes()...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := message.VerifyProposal(req.ChainID, proposal, vals, req.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// verifyCommitRequest is the data payload of the verify_commit service, in
+// tendermint RPC JSON encoding (64-bit integers as strings).
+type verifyCommitRequest struct {
+	ChainID    string             `json:"chain_id"`
+	Height     int64              `json:"height"`
+	BlockID    types.BlockID      `json:"block_id"`
+	Commit     *types.Commit      `json:"commit"`
+	Validators []*types.Validator `json:"validators"`
+}
+
+func verifyCommit(data json.RawMessage) (interface{}, error) {
+	var req verifyCommitRequest
+	if err := tmjson.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse verify_commit request: %w", err)
+	}
+
+	vals := &types.ValidatorSet{Validators: req.Validators}
+	return newCommitCheck(message.VerifyCommit(req.ChainID, req.BlockID, req.Height, req.Commit, vals))
+}
+
+// commitCheck is the result of verify_commit and verify_rpc_commit: the vote
+// audit of the commit, valid if it reached the quorum, or only Error if the
+// commit is not for the expected block and validator set.
+type commitCheck struct {
+	*message.CommitResult
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+func newCommitCheck(res *message.CommitResult, err error) (interface{}, error) {
+	switch {
+	case err == nil:
+		return commitCheck{CommitResult: res, Valid: res.QuorumReached}, nil
+	case isVerificationFailure(err):
+		return commitCheck{Error: err.Error()}, nil
+	default:
+		return nil, err
+	}
+}
+
+// verifyCommitTrustingRequest is the data payload of the
+// verify_commit_trusting service. TrustLevel is a fraction such as "1/3" and
+// defaults to message.DefaultTrustLevel.
+type verifyCommitTrustingRequest struct {
+	ChainID               string             `json:"chain_id"`
+	Commit                *types.Commit      `json:"commit"`
+	TrustedValidators     []*types.Validator `json:"trusted_validators"`
+	TrustedValidatorsHash tmbytes.HexBytes   `json:"trusted_validators_hash,omitempty"`
+	TrustLevel            string             `json:"trust_level,omitempty"`
+}
+
+func verifyCommitTrusting(data json.RawMessage) (interface{}, error) {
+	var req verifyCommitTrustingRequest
+	if err := tmjson.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse verify_commit_trusting request: %w", err)
+	}
+
+	trustLevel := message.DefaultTrustLevel
+	if req.TrustLevel != "" {
+		var err error
+		if trustLevel, err = tmmath.ParseFraction(req.TrustLevel); err != nil {
+			return nil, fmt.Errorf("parse trust level: %w", err)
+		}
+	}
+
+	vals := &types.ValidatorSet{Validators: req.TrustedValidators}
+	res, err := message.VerifyCommitTrusting(req.ChainID, req.Commit, vals, req.TrustedValidatorsHash, trustLevel)
+	switch {
+	case err == nil:
+		return trustingCheck{TrustingResult: res, Valid: res.Trusted}, nil
+	case isVerificationFailure(err):
+		return trustingCheck{Error: err.Error()}, nil
+	default:
+		return nil, err
+	}
+}
+
+// trustingCheck is the result of verify_commit_trusting: the overlap of the
+// commit with the trusted set, valid if it is trusted, or only Error if the
+// trusted set does not match TrustedValidatorsHash.
+type trustingCheck struct {
+	*message.TrustingResult
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// verifyHeaderFieldRequest is the data payload of the verify_header_field
+// service. Value is the field value in tendermint RPC JSON encoding, e.g. a
+// hex string for app_hash.
+type verifyHeaderFieldRequest struct {
+	BlockHash tmbytes.HexBytes `json:"block_hash"`
+	Field     string           `json:"field"`
+	Value     json.RawMessage  `json:"value"`
+	Proof     *merkle.Proof    `json:"proof"`
+}
+
+type verifyHeaderFieldResult struct {
+	Field string `json:"field"`
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+func verifyHeaderField(data json.RawMessage) (interface{}, error) {
+	var req verifyHeaderFieldRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse verify_header_field request: %w", err)
+	}
+
+	field, err := header.ParseHeaderField(req.Field)
+	if err != nil {
+		return nil, err
+	}
+	value, err := header.DecodeFieldValue(field, req.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	res := verifyHeaderFieldResult{Field: field.String(), Valid: true}
+	if err := header.VerifyHeaderField(req.BlockHash, field, value, req.Proof); err != nil {
+		res.Valid = false
+		res.Error = err.Error()
+	}
+	return res, nil
+}
+
+// verifyHeaderChainRequest is the data payload of the verify_header_chain
+// service: recorded /header, /commit or /block responses in height order.
+type verifyHeaderChainRequest struct {
+	Headers []json.RawMessage `json:"headers"`
+}
+
+type verifyHeaderChainResult struct {
+	FromHeight int64            `json:"from_height"`
+	ToHeight   int64            `json:"to_height"`
+	LastHash   tmbytes.HexBytes `json:"last_hash"`
+	Valid      bool             `json:"valid"`
+	BrokenAt   int64            `json:"broken_at,omitempty"`
+	Error      string           `json:"error,omitempty"`
+}
+
+func verifyHeaderChain(data json.RawMessage) (interface{}, error) {
+	var req verifyHeaderChainRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse verify_header_chain request: %w", err)
+	}
+	if len(req.Headers) == 0 {
+		return nil, errors.New("no headers")
+	}
+
+	headers := make([]*types.Header, len(req.Headers))
+	for i, raw := range req.Headers {
+		h, err := rpc.ParseHeader(raw)
+		if err != nil {
+			return nil, fmt.Errorf("header #%d: %w", i, err)
+		}
+		headers[i] = h
+	}
+
+	last := headers[len(headers)-1]
+	res := verifyHeaderChainResult{
+		FromHeight: headers[0].Height,
+		ToHeight:   last.Height,
+		LastHash:   last.Hash(),
+		Valid:      true,
+	}
+	if i, err := header.VerifyHeaderChain(headers); err != nil {
+		res.Valid = false
+		res.BrokenAt = headers[i].Height
+		res.Error = err.Error()
+	}
+	return res, nil
+}
+
+// verifyRPCCommitRequest is the data payload of the verify_rpc_commit service:
+// a recorded /commit response and every page of the /validators response at
+// the same height.
+type verifyRPCCommitRequest struct {
+	Commit         json.RawMessage `json:"commit"`
+	ValidatorPages validatorPages  `json:"validator_pages"`
+}
+
+func verifyRPCCommit(data json.RawMessage) (interface{}, error) {
+	var req verifyRPCCommitRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse verify_rpc_commit request: %w", err)
+	}
+
+	sh, err := rpc.ParseCommit(req.Commit)
+	if err != nil {
+		return nil, err
+	}
+	vals, err := rpc.ParseValidatorSet(req.ValidatorPages.bytes()...)
+	if err != nil {
+		return nil, err
+	}
+
+	valsHash, err := validator.Hash(vals.Validators)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(valsHash, sh.ValidatorsHash) {
+		return newCommitCheck(nil, fmt.Errorf("%w: validators hash %X does not match header validators hash %X",
+			message.ErrHashMismatch, valsHash, sh.ValidatorsHash))
+	}
+	if hash := sh.Header.Hash(); !bytes.Equal(hash, sh.Commit.BlockID.Hash) {
+		return newCommitCheck(nil, fmt.Errorf("%w: commit signs block %X, header hash is %X",
+			message.ErrHashMismatch, sh.Commit.BlockID.Hash, hash))
+	}
+
+	return newCommitCheck(message.VerifyCommitBatch(sh.ChainID, sh.Commit.BlockID, sh.Height, sh.Commit, vals, 0))
+}
+
+// validatorPages are the pages of a recorded /validators response. Requests
+// give them as validator_pages, apart from the plain validator lists of
+// tendermint JSON named validators.
+type validatorPages []json.RawMessage
+
+func (p validatorPages) bytes() [][]byte {
+	pages := make([][]byte, len(p))
+	for i, page := range p {
+		pages[i] = page
+	}
+	return pages
+}
+
+// rpcLightBlock is a recorded /commit response and every page of the
+// /validators response at the same height.
+type rpcLightBlock struct {
+	Commit         json.RawMessage `json:"commit"`
+	ValidatorPages validatorPages  `json:"validator_pages"`
+}
+
+func (b rpcLightBlock) parse() (*types.LightBlock, error) {
+	return rpc.ParseLightBlock(b.Commit, b.ValidatorPages.bytes()...)
+}
+
+// lightSource serves light blocks from LightBlocks or, if there are none,
+// fetches them from RPCURL, which must be one of lightRPCURLs.
+type lightSource struct {
+	LightBlocks []rpcLightBlock `json:"light_blocks,omitempty"`
+	RPCURL      string          `json:"rpc_url,omitempty"`
+}
+
+func (src lightSource) provider() (light.Provider, error) {
+	switch {
+	case len(src.LightBlocks) > 0:
+		blocks := make([]*types.LightBlock, len(src.LightBlocks))
+		for i, b := range src.LightBlocks {
+			var err error
+			if blocks[i], err = b.parse(); err != nil {
+				return nil, fmt.Errorf("light block #%d: %w", i, err)
+			}
+		}
+		return light.NewFixtures(blocks...), nil
+	case src.RPCURL != "":
+		if !rpcAllowed(src.RPCURL) {
+			return nil, fmt.Errorf("rpc_url %q is not an allowed RPC endpoint", src.RPCURL)
+		}
+		return &light.HTTPProvider{URL: src.RPCURL}, nil
+	default:
+		return nil, errors.New("light_blocks or rpc_url is required")
+	}
+}
+
+// lightVerifyRequest is the data payload of the light_verify service. The
+// light blocks needed on the way to TargetHeight come from the primary
+// source, given inline. Without Trusted, verification starts from the highest
+// block of ChainID in the trusted store at or below the target. The verified
+// block is then compared with the same height of every witness, and any
+// conflict is reported with its attack evidence instead of being trusted.
+// The verified block is saved to the trusted store, and Saved set, only when
+// verification starts from the store or from one of lightTrustRoots and runs
+// at the system clock.
+// Durations use Go syntax such as "336h", and Now, the RFC 3339 time to verify
+// at, lets recorded blocks be replayed after their trusting period.
+type lightVerifyRequest struct {
+	lightSource
+	ChainID        string         `json:"chain_id,omitempty"`
+	Trusted        *rpcLightBlock `json:"trusted,omitempty"`
+	TargetHeight   int64          `json:"target_height"`
+	Witnesses      []lightSource  `json:"witnesses,omitempty"`
+	TrustingPeriod string         `json:"trusting_period,omitempty"`
+	MaxClockDrift  string         `json:"max_clock_drift,omitempty"`
+	TrustLevel     string         `json:"trust_level,omitempty"`
+	Now            *time.Time     `json:"now,omitempty"`
+}
+
+type lightVerifyResult struct {
+	Valid          bool             `json:"valid"`
+	VerifiedHeight int64            `json:"verified_height"`
+	VerifiedHash   tmbytes.HexBytes `json:"verified_hash"`
+	Trace          []light.Step     `json:"trace"`
+	Detection      *light.Detection `json:"detection,omitempty"`
+	Saved          bool             `json:"saved"`
+	Error          string           `json:"error,omitempty"`
+}
+
+func lightVerify(data json.RawMessage) (interface{}, error) {
+	var req lightVerifyRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse light_verify request: %w", err)
+	}
+
+	opts := light.DefaultOptions()
+	var err error
+	if req.TrustingPeriod != "" {
+		if opts.TrustingPeriod, err = time.ParseDuration(req.TrustingPeriod); err != nil {
+			return nil, fmt.Errorf("parse trusting period: %w", err)
+		}
+	}
+	if req.MaxClockDrift != "" {
+		if opts.MaxClockDrift, err = time.ParseDuration(req.MaxClockDrift); err != nil {
+			return nil, fmt.Errorf("parse max clock drift: %w", err)
+		}
+	}
+	if req.TrustLevel != "" {
+		if opts.TrustLevel, err = tmmath.ParseFraction(req.TrustLevel); err != nil {
+			return nil, fmt.Errorf("parse trust level: %w", err)
+		}
+	}
+
+	// a block verified at another time or from an arbitrary anchor must
+	// not become an anchor itself
+	save := trustedStore != nil && req.Now == nil
+	var trusted *types.LightBlock
+	if req.Trusted != nil {
+		if trusted, err = req.Trusted.parse(); err != nil {
+			return nil, fmt.Errorf("trusted light block: %w", err)
+		}
+		save = save && isTrustRoot(trusted)
+	} else if trusted, err = anchor(req.ChainID, req.TargetHeight); err != nil {
+		return nil, err
+	}
+
+	provider, err := req.provider()
+	if err != nil {
+		return nil, err
+	}
+	witnesses := make([]light.Provider, len(req.Witnesses))
+	for i, w := range req.Witnesses {
+		if witnesses[i], err = w.provider(); err != nil {
+			return nil, fmt.Errorf("witness #%d: %w", i, err)
+		}
+	}
+
+	var now func() time.Time
+	if req.Now != nil {
+		now = func() time.Time { return *req.Now }
+	}
+	client, err := light.NewClient(provider, opts, now)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lightTimeout)
+	defer cancel()
+	verified, err := client.VerifyToHeight(ctx, trusted, req.TargetHeight)
+	if verified == nil || err != nil && !isVerificationFailure(err) {
+		// the trusted block is invalid, the chain could not be fetched or
+		// the target is invalid, so nothing was disproved
+		return nil, err
+	}
+	res := lightVerifyResult{
+		Valid:          err == nil,
+		VerifiedHeight: verified.Verified.Height,
+		VerifiedHash:   verified.Verified.Hash(),
+		Trace:          verified.Trace,
+	}
+	if err != nil {
+		res.Error = err.Error()
+		return res, nil
+	}
+
+	if len(witnesses) > 0 && len(verified.Blocks) > 0 {
+		if res.Detection, err = client.DetectForks(ctx, trusted, verified, witnesses); err != nil {
+			return nil, err
+		}
+		if res.Detection.Attacked() {
+			res.Valid = false
+			res.Error = fmt.Sprintf("light client attack detected: %d witness(es) proved a conflicting header",
+				len(res.Detection.Conflicts))
+			return res, nil
+		}
+	}
+
+	if save {
+		if err := trustedStore.Save(verified.Verified); err != nil {
+			return nil, fmt.Errorf("save trusted light block: %w", err)
+		}
+		res.Saved = true
+	}
+	return res, nil
+}
+
+// isTrustRoot reports whether lb is one of lightTrustRoots.
+func isTrustRoot(lb *types.LightBlock) bool {
+	for _, root := range lightTrustRoots {
+		if root.ChainID == lb.ChainID && root.Height == lb.Height && bytes.Equal(root.Hash, lb.Hash()) {
+			return true
+		}
+	}
+	return false
+}
+
+// rpcAllowed reports whether rpcURL is one of lightRPCURLs, ignoring a
+// trailing slash.
+func rpcAllowed(rpcURL string) bool {
+	for _, u := range lightRPCURLs {
+		if strings.TrimSuffix(u, "/") == strings.TrimSuffix(rpcURL, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+// anchor returns the highest light block of chainID in the trusted store at or
+// below height, or the latest one if height is 0.
+func anchor(chainID string, height int64) (*types.LightBlock, error) {
+	if trustedStore == nil {
+		return nil, errors.New("trusted light block is required, no trusted store is configured")
+	}
+	if chainID == "" {
+		return nil, errors.New("chain_id is required without a trusted light block")
+	}
+	if height == 0 {
+		return trustedStore.Latest(chainID)
+	}
+	blocks, err := trustedStore.Range(chainID, 0, height)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("%w: no trusted light block of %s at or below height %d", store.ErrNotFound, chainID, height)
+	}
+	return blocks[len(blocks)-1], nil
+}
+
+// trustedLightBlockRequest is the data payload of the trusted_light_block
+// service. Height 0 returns the latest trusted block; with To set, every
+// trusted block from Height to To is returned.
+type trustedLightBlockRequest struct {
+	ChainID string `json:"chain_id"`
+	Height  int64  `json:"height,omitempty"`
+	To      int64  `json:"to,omitempty"`
+}
+
+type trustedLightBlockResult struct {
+	Height       int64               `json:"height"`
+	Hash         tmbytes.HexBytes    `json:"hash"`
+	Time         time.Time           `json:"time"`
+	SignedHeader *types.SignedHeader `json:"signed_header"`
+	ValidatorSet *types.ValidatorSet `json:"validator_set"`
+}
+
+func newTrustedLightBlockResult(lb *types.LightBlock) trustedLightBlockResult {
+	return trustedLightBlockResult{
+		Height:       lb.Height,
+		Hash:         lb.Hash(),
+		Time:         lb.Time,
+		SignedHeader: lb.SignedHeader,
+		ValidatorSet: lb.ValidatorSet,
+	}
+}
+
+func trustedLightBlock(data json.RawMessage) (interface{}, error) {
+	var req trustedLightBlockRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse trusted_light_block request: %w", err)
+	}
+	if trustedStore == nil {
+		return nil, errors.New("no trusted store is configured")
+	}
+
+	if req.To != 0 {
+		blocks, err := trustedStore.Range(req.ChainID, req.Height, req.To)
+		if err != nil {
+			return nil, err
+		}
+		res := make([]trustedLightBlockResult, len(blocks))
+		for i, lb := range blocks {
+			res[i] = newTrustedLightBlockResult(lb)
+		}
+		return res, nil
+	}
+
+	var (
+		lb  *types.LightBlock
+		err error
+	)
+	if req.Height == 0 {
+		lb, err = trustedStore.Latest(req.ChainID)
+	} else {
+		lb, err = trustedStore.LightBlock(req.ChainID, req.Height)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newTrustedLightBlockResult(lb), nil
+}
+
+// pruneTrustedRequest is the data payload of the prune_trusted service. It
+// deletes the trusted blocks of ChainID below BelowHeight and, with OlderThan
+// set (in Go duration syntax), those older than that, except the latest.
+type pruneTrustedRequest struct {
+	ChainID     string `json:"chain_id"`
+	BelowHeight int64  `json:"below_height,omitempty"`
+	OlderThan   string `json:"older_than,omitempty"`
+}
+
+type pruneTrustedResult struct {
+	Pruned int `json:"pruned"`
+}
+
+func pruneTrusted(data json.RawMessage) (interface{}, error) {
+	var req pruneTrustedRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse prune_trusted request: %w", err)
+	}
+	if trustedStore == nil {
+		return nil, errors.New("no trusted store is configured")
+	}
+
+	var res pruneTrustedResult
+	if req.BelowHeight != 0 {
+		n, err := trustedStore.PruneBelow(req.ChainID, req.BelowHeight)
+		if err != nil {
+			return nil, err
+		}
+		res.Pruned += n
+	}
+	if req.OlderThan != "" {
+		age, err := time.ParseDuration(req.OlderThan)
+		if err != nil {
+			return nil, fmt.Errorf("parse older_than: %w", err)
+		}
+		n, err := trustedStore.PruneBefore(req.ChainID, time.Now().Add(-age))
+		if err != nil {
+			return nil, err
+		}
+		res.Pruned += n
+	}
+	return res, nil
+}
+
+// validatorSetHashRequest is the data payload of the validator_set_hash
+// service. The validators may come in any order: they are hashed in the order
+// of a tendermint validator set, by voting power, descending, then by address,
+// which the proof indexes refer to. Prove lists the addresses, in bech32 or
+// hex, of the validators to return inclusion proofs for.
+type validatorSetHashRequest struct {
+	Validators []*types.Validator `json:"validators"`
+	Prove      []string           `json:"prove,omitempty"`
+}
+
+type validatorSetHashResult struct {
+	ValidatorsHash tmbytes.HexBytes           `json:"validators_hash"`
+	Proofs         []validator.InclusionProof `json:"proofs,omitempty"`
+}
+
+func validatorSetHash(data json.RawMessage) (interface{}, error) {
+	var req validatorSetHashRequest
+	if err := tmjson.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse validator_set_hash request: %w", err)
+	}
+
+	vals, err := validator.SortByVotingPower(req.Validators)
+	if err != nil {
+		return nil, err
+	}
+	root, proofs, err := validator.HashWithProofs(vals)
+	if err != nil {
+		return nil, err
+	}
+
+	res := validatorSetHashResult{ValidatorsHash: root}
+	for _, addr := range req.Prove {
+		address, err := validator.ParseAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		proof, err := validator.FindProof(proofs, address)
+		if err != nil {
+			return nil, err
+		}
+		res.Proofs = append(res.Proofs, *proof)
+	}
+	return res, nil
+}
+
+// txInclusionProofRequest is the data payload of the tx_inclusion_proof
+// service: a recorded /block response and the index of the tx to prove.
+type txInclusionProofRequest struct {
+	Block json.RawMessage `json:"block"`
+	Index int             `json:"index"`
+}
+
+type txInclusionProofResult struct {
+	BlockHash   tmbytes.HexBytes      `json:"block_hash"`
+	Height      int64                 `json:"height"`
+	Proof       *txproof.BlockTxProof `json:"proof"`
+	DecodedTx   *txdecode.Tx          `json:"decoded_tx,omitempty"`
+	DecodeError string                `json:"decode_error,omitempty"`
+}
+
+func txInclusionProof(data json.RawMessage) (interface{}, error) {
+	var req txInclusionProofRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse tx_inclusion_proof request: %w", err)
+	}
+
+	_, block, err := rpc.ParseBlock(req.Block)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := txproof.ProveTxInBlock(&block.Header, block.Data.Txs, req.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	res := txInclusionProofResult{
+		BlockHash: block.Header.Hash(),
+		Height:    block.Header.Height,
+		Proof:     proof,
+	}
+	res.DecodedTx, res.DecodeError = decodeTx(proof.Tx)
+	return res, nil
+}
+
+// verifyTxInclusionRequest is the data payload of the verify_tx_inclusion
+// service, with a proof as returned by tx_inclusion_proof.
+type verifyTxInclusionRequest struct {
+	BlockHash tmbytes.HexBytes      `json:"block_hash"`
+	Proof     *txproof.BlockTxProof `json:"proof"`
+}
+
+type verifyTxInclusionResult struct {
+	TxHash      tmbytes.HexBytes `json:"tx_hash"`
+	Valid       bool             `json:"valid"`
+	Error       string           `json:"error,omitempty"`
+	DecodedTx   *txdecode.Tx     `json:"decoded_tx,omitempty"`
+	DecodeError string           `json:"decode_error,omitempty"`
+}
+
+func verifyTxInclusion(data json.RawMessage) (interface{}, error) {
+	var req verifyTxInclusionRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse verify_tx_inclusion request: %w", err)
+	}
+	if req.Proof == nil {
+		return nil, errors.New("missing proof")
+	}
+
+	res := verifyTxInclusionResult{TxHash: req.Proof.Tx.Hash(), Valid: true}
+	if err := txproof.VerifyTxInBlock(req.BlockHash, req.Proof); err != nil {
+		res.Valid = false
+		res.Error = err.Error()
+		return res, nil
+	}
+	res.DecodedTx, res.DecodeError = decodeTx(req.Proof.Tx)
+	return res, nil
+}
+
+// txResultProofRequest is the data payload of the tx_result_proof service: a
+// recorded /block_results response, the header of the next block (as a
+// /header, /commit or /block response) and the index of the tx.
+type txResultProofRequest struct {
+	BlockResults json.RawMessage `json:"block_results"`
+	NextHeader   json.RawMessage `json:"next_header"`
+	Index        int             `json:"index"`
+}
+
+type txResultProofResult struct {
+	Height        int64                     `json:"height"`
+	NextBlockHash tmbytes.HexBytes          `json:"next_block_hash"`
+	Proof         *txproof.BlockResultProof `json:"proof"`
+}
+
+func txResultProof(data json.RawMessage) (interface{}, error) {
+	var req txResultProofRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse tx_result_proof request: %w", err)
+	}
+
+	height, results, err := rpc.ParseBlockResults(req.BlockResults)
+	if err != nil {
+		return nil, err
+	}
+	next, err := rpc.ParseHeader(req.NextHeader)
+	if err != nil {
+		return nil, err
+	}
+	if next.Height != height+1 {
+		return nil, fmt.Errorf("results of height %d are committed to header %d, got header %d", height, height+1, next.Height)
+	}
+
+	proof, err := txproof.ProveResultInBlock(next, results, req.Index)
+	if err != nil {
+		return nil, err
+	}
+	return txResultProofResult{
+		Height:        height,
+		NextBlockHash: next.Hash(),
+		Proof:         proof,
+	}, nil
+}
+
+// verifyTxResultRequest is the data payload of the verify_tx_result service,
+// with a proof as returned by tx_result_proof.
+type verifyTxResultRequest struct {
+	NextBlockHash tmbytes.HexBytes          `json:"next_block_hash"`
+	Proof         *txproof.BlockResultProof `json:"proof"`
+}
+
+type verifyTxResultResult struct {
+	Code    uint32 `json:"code"`
+	Success bool   `json:"success"`
+	Valid   bool   `json:"valid"`
+	Error   string `json:"error,omitempty"`
+}
+
+func verifyTxResult(data json.RawMessage) (interface{}, error) {
+	var req verifyTxResultRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse verify_tx_result request: %w", err)
+	}
+	if req.Proof == nil {
+		return nil, errors.New("missing proof")
+	}
+
+	res := verifyTxResultResult{Code: req.Proof.Result.Code, Valid: true}
+	if err := txproof.VerifyResultInBlock(req.NextBlockHash, req.Proof); err != nil {
+		res.Valid = false
+		res.Error = err.Error()
+		return res, nil
+	}
+	res.Success = req.Proof.Result.Code == abci.CodeTypeOK
+	return res, nil
+}
+
+// decodeTx decodes a proven tx for the response. Txs that are not Cosmos SDK
+// txs are still proven, so a decoding failure is reported next to the proof
+// rather than failing the request.
+func decodeTx(tx types.Tx) (*txdecode.Tx, string) {
+	decoded, err := txdecode.Decode(tx)
+	if err != nil {
+		return nil, err.Error()
+	}
+	return decoded, ""
+}
+
+// verifyStateRequest is the data payload of the verify_state service: a
+// recorded /abci_query response with prove=true, and either the header at the
+// next height (as a /header, /commit or /block response) or an app hash.
+type verifyStateRequest struct {
+	ABCIQuery json.RawMessage  `json:"abci_query"`
+	Header    json.RawMessage  `json:"header,omitempty"`
+	AppHash   tmbytes.HexBytes `json:"app_hash,omitempty"`
+}
+
+type verifyStateResult struct {
+	Store  string           `json:"store"`
+	Key    tmbytes.HexBytes `json:"key"`
+	Value  []byte           `json:"value,omitempty"`
+	Exists bool             `json:"exists"`
+	Height int64            `json:"height"`
+	Valid  bool             `json:"valid"`
+	Error  string           `json:"error,omitempty"`
+}
+
+func verifyState(data json.RawMessage) (interface{}, error) {
+	var req verifyStateRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse verify_state request: %w", err)
+	}
+
+	query, err := rpc.ParseABCIQuery(req.ABCIQuery)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := state.ProofFromOps(query.ProofOps, query.Key, query.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkStoreProof(proof, query.Height, req.Header, req.AppHash)
+}
+
+// checkStoreProof verifies proof against the header at height+1 if one is
+// given, or else against appHash.
+func checkStoreProof(proof *state.StoreProof, height int64, rawHeader json.RawMessage, appHash []byte) (*verifyStateResult, error) {
+	res := &verifyStateResult{
+		Store:  proof.StoreName,
+		Key:    proof.Key,
+		Value:  proof.Value,
+		Exists: proof.Exists(),
+		Height: height,
+		Valid:  true,
+	}
+
+	var err error
+	switch {
+	case len(rawHeader) > 0:
+		h, perr := rpc.ParseHeader(rawHeader)
+		if perr != nil {
+			return nil, perr
+		}
+		err = proof.VerifyAgainstHeader(h, height)
+	case len(appHash) > 0:
+		err = proof.Verify(appHash)
+	default:
+		return nil, errors.New("missing header or app hash")
+	}
+
+	if err != nil {
+		res.Valid = false
+		res.Error = err.Error()
+	}
+	return res, nil
+}
+
+// contractKey selects a contract storage key: raw bytes, a cw-storage-plus
+// Item namespace, or a cw-storage-plus Map namespace with its key elements.
+type contractKey struct {
+	Raw  tmbytes.HexBytes `json:"raw,omitempty"`
+	Item string           `json:"item,omitempty"`
+	Map  string           `json:"map,omitempty"`
+	Keys []keyElement     `json:"keys,omitempty"`
+}
+
+// keyElement is a Map key element, given either as a UTF-8 string or as
+// {"type": ..., "value": ...} with a type of state.KeyElement, such as
+// {"type": "u64", "value": 42} or {"type": "hex", "value": "0a0b"}.
+type keyElement struct {
+	Type  string
+	Value string
+}
+
+func (e *keyElement) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &e.Value); err == nil {
+		e.Type = ""
+		return nil
+	}
+
+	var typed struct {
+		Type  string          `json:"type"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return err
+	}
+	e.Type = typed.Type
+	// integers may be given as JSON numbers or strings
+	if err := json.Unmarshal(typed.Value, &e.Value); err != nil {
+		var n json.Number
+		if err := json.Unmarshal(typed.Value, &n); err != nil {
+			return fmt.Errorf("key element value must be a string or a number: %w", err)
+		}
+		e.Value = n.String()
+	}
+	return nil
+}
+
+func (k contractKey) bytes() ([]byte, error) {
+	switch {
+	case len(k.Raw) > 0:
+		return k.Raw, nil
+	case k.Item != "":
+		return state.ItemKey(k.Item), nil
+	case k.Map != "":
+		keys := make([][]byte, len(k.Keys))
+		for i, key := range k.Keys {
+			var err error
+			if keys[i], err = state.KeyElement(key.Type, key.Value); err != nil {
+				return nil, fmt.Errorf("map key #%d: %w", i, err)
+			}
+		}
+		return state.MapKey(k.Map, keys...)
+	default:
+		return nil, errors.New("contract key needs raw, item or map")
+	}
+}
+
+// verifyContractStateRequest is the data payload of the
+// verify_contract_state service. ABCIQuery is a /abci_query response for
+// path /store/wasm/key with prove=true; Header and AppHash are as for
+// verify_state.
+type verifyContractStateRequest struct {
+	Contract  string           `json:"contract"`
+	Key       contractKey      `json:"key"`
+	ABCIQuery json.RawMessage  `json:"abci_query"`
+	Header    json.RawMessage  `json:"header,omitempty"`
+	AppHash   tmbytes.HexBytes `json:"app_hash,omitempty"`
+}
+
+func verifyContractState(data json.RawMessage) (interface{}, error) {
+	var req verifyContractStateRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("parse verify_contract_state request: %w", err)
+	}
+
+	contract, err := state.ContractAddress(req.Contract)
+	if err != nil {
+		return nil, err
+	}
+	key, err := req.Key.bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := rpc.ParseABCIQuery(req.ABCIQuery)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := state.ProofFromOps(query.ProofOps, query.Key, query.Value)
+	if err != nil {
+		return nil, err
+	}
+	if err := state.CheckContractKey(proof, contract, key); err != nil {
+		return nil, err
+	}
+
+	return checkStoreProof(proof, query.Height, req.Header, req.AppHash)
+}
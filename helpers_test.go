@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
+)
+
+const testChainID = "test-chain"
+
+// genSignedHeader returns a header of height 10 signed by every validator of a
+// new set of n, and that set.
+func genSignedHeader(t *testing.T, n int) (*types.SignedHeader, *types.ValidatorSet) {
+	t.Helper()
+	ctx := context.Background()
+	keys := make(map[string]types.PrivValidator, n)
+	vals := make([]*types.Validator, n)
+	for i := range vals {
+		key := types.NewMockPV()
+		pubKey, err := key.GetPubKey(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[string(pubKey.Address())] = key
+		vals[i] = types.NewValidator(pubKey, 10)
+	}
+	valSet := types.NewValidatorSet(vals)
+
+	h := &types.Header{
+		Version:            version.Consensus{Block: version.BlockProtocol},
+		ChainID:            testChainID,
+		Height:             10,
+		Time:               time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		ValidatorsHash:     valSet.Hash(),
+		NextValidatorsHash: valSet.Hash(),
+		ProposerAddress:    valSet.Proposer.Address,
+	}
+	blockID := types.BlockID{
+		Hash:          h.Hash(),
+		PartSetHeader: types.PartSetHeader{Total: 1, Hash: tmhash.Sum([]byte("parts"))},
+	}
+	sigs := make([]types.CommitSig, n)
+	for i, val := range valSet.Validators {
+		vote := &types.Vote{
+			Type:             tmproto.PrecommitType,
+			Height:           h.Height,
+			BlockID:          blockID,
+			Timestamp:        h.Time,
+			ValidatorAddress: val.Address,
+			ValidatorIndex:   int32(i),
+		}
+		pb := vote.ToProto()
+		if err := keys[string(val.Address)].SignVote(ctx, testChainID, pb); err != nil {
+			t.Fatal(err)
+		}
+		vote.Signature = pb.Signature
+		sigs[i] = vote.CommitSig()
+	}
+	return &types.SignedHeader{Header: h, Commit: types.NewCommit(h.Height, 0, blockID, sigs)}, valSet
+}
+
+// commitResponse returns the /commit result of sh.
+func commitResponse(t *testing.T, sh *types.SignedHeader) json.RawMessage {
+	t.Helper()
+	bz, err := tmjson.Marshal(struct {
+		SignedHeader *types.SignedHeader `json:"signed_header"`
+		Canonical    bool                `json:"canonical"`
+	}{sh, true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bz
+}
+
+// validatorsResponse returns vals as a single-page /validators result.
+func validatorsResponse(t *testing.T, height int64, vals *types.ValidatorSet) json.RawMessage {
+	t.Helper()
+	bz, err := tmjson.Marshal(struct {
+		BlockHeight int64              `json:"block_height"`
+		Validators  []*types.Validator `json:"validators"`
+		Count       int                `json:"count"`
+		Total       int                `json:"total"`
+	}{height, vals.Validators, vals.Size(), vals.Size()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bz
+}
+
+// call sends a request for typeService with data through handleRequest and
+// returns the response with its result decoded into a map.
+func call(t *testing.T, typeService string, data interface{}) (*Response, map[string]interface{}) {
+	t.Helper()
+	payload, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(request{TypeService: typeService, Data: payload})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := handleRequest(body, "test")
+	if res.Result == nil {
+		return res, nil
+	}
+	bz, err := json.Marshal(res.Result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(bz, &result); err != nil {
+		t.Fatal(err)
+	}
+	return res, result
+}
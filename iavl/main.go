@@ -4,7 +4,9 @@ import (
 	// "bytes"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"log"
 	"math/bits"
 
 	// "math/big"
@@ -14,6 +16,9 @@ import (
 	// "github.com/stretchr/testify/require"
 	"github.com/tendermint/tendermint/crypto/merkle"
 	"github.com/tendermint/tendermint/crypto/tmhash"
+
+	"server/iavlTree/txdecode"
+	"server/iavlTree/txproof"
 )
 
 var (
@@ -50,30 +55,30 @@ func getSplitPoint(length int64) int64 {
 }
 
 // synthetic txs to Txs
-func b64ToHex(txs ...string) types.Txs {
+func b64ToHex(txs ...string) (types.Txs, error) {
 	var txHexes []types.Tx
-	for _, tx := range txs {
+	for i, tx := range txs {
 		// decode base64
 		b, err := base64.StdEncoding.DecodeString(tx)
 		if err != nil {
-			panic(err)
+			return nil, fmt.Errorf("decode tx #%d: %w", i, err)
 		}
 		txHexes = append(txHexes, b)
 	}
-	return txHexes
+	return txHexes, nil
 }
 
-func txHashToBytes(txHashs []string) types.Txs {
+func txHashToBytes(txHashs []string) (types.Txs, error) {
 	var txs types.Txs
-	for _, txHash := range txHashs {
+	for i, txHash := range txHashs {
 		txBytes, err := hex.DecodeString(txHash)
 		if err != nil {
-			panic(err)
+			return nil, fmt.Errorf("decode tx #%d: %w", i, err)
 		}
 		txs = append(txs, txBytes)
 
 	}
-	return txs
+	return txs, nil
 }
 
 func main() {
@@ -83,7 +88,10 @@ func main() {
 		"CoICCv8BCiQvY29zbXdhc20ud2FzbS52MS5Nc2dFeGVjdXRlQ29udHJhY3QS1gEKK29yYWkxa21qcmxkZ2ozd2FrZjRxbWV1ZHJjZWQwbTl5N3FoMG01YXNmMngSK29yYWkxbmQ0cjA1M2Uza2dlZGdsZDJ5bWVuOGw5eXJ3OHhwanlhYWw3ajUaensiaW5jcmVhc2VfYWxsb3dhbmNlIjp7ImFtb3VudCI6Ijk5OTk5OTk5OTk5OTk5OTk5OTk5OTk5OTk5OTk5OSIsInNwZW5kZXIiOiJvcmFpMXlubWQyY2VtcnloY3d0anEzYWRoY3dheXJtODlsMmNyNHR3czR2In19EmUKUApGCh8vY29zbW9zLmNyeXB0by5zZWNwMjU2azEuUHViS2V5EiMKIQIS0r5ZO0japSdfqZC7mT4u85eoP1xyHn3n/x2lFnWHlxIECgIIARgCEhEKCwoEb3JhaRIDNzM2EO79CBpAJRZ6ytb+q1iTUkasCMly7iF+twZYIHEbUtUMpJfabKBkq5GKPPuaezY5k48ivQknLdyg5lu6ojv21NXamaPSVA==",
 	}
 
-	txs := b64ToHex(txsHex...)
+	txs, err := b64ToHex(txsHex...)
+	if err != nil {
+		log.Fatal(err)
+	}
 	root := txs.Hash()
 
 	fmt.Printf("%x\n", root)
@@ -92,10 +100,7 @@ func main() {
 	// 	[]byte(txs[0]),
 	// }
 
-	txBzs := make([][]byte, len(txs))
-	for i := 0; i < len(txs); i++ {
-		txBzs[i] = txs[i].Hash()
-	}
+	txBzs := txproof.Leaves(txs)
 	root2 := merkle.HashFromByteSlices(txBzs)
 	fmt.Printf("%x\n", root2)
 
@@ -112,6 +117,22 @@ func main() {
 	// aunts
 	fmt.Printf("aunts : %x\n", proofs[0].Aunts[0])
 
+	txProof, err := txproof.TxInclusionProof(txs, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("validate :", txProof.Validate(root))
+
+	decoded, err := txdecode.Decode(txProof.Data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	decodedJSON, err := json.Marshal(decoded)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("decoded :", string(decodedJSON))
+
 	// // bytes of a charactor
 	// fmt.Printf("%x\n", []byte("a"))
 	// hashA := leafHash([]byte("a"))
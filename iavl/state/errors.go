@@ -0,0 +1,22 @@
+package state
+
+import "errors"
+
+// Errors returned, wrapped, by this package, so callers can classify them
+// with errors.Is. A nil header is reported with header.ErrInvalidHeader.
+var (
+	// ErrMalformedProof is a store proof that is missing, incomplete or
+	// cannot be decoded.
+	ErrMalformedProof = errors.New("malformed store proof")
+	// ErrInvalidProof is a store proof that does not verify.
+	ErrInvalidProof = errors.New("invalid store proof")
+	// ErrKeyMismatch is a store proof of another store or key than the
+	// one requested.
+	ErrKeyMismatch = errors.New("proof is for another key")
+	// ErrHeightMismatch is a header that does not commit to the state at
+	// the queried height.
+	ErrHeightMismatch = errors.New("header does not commit to the query height")
+	// ErrInvalidKey is a contract address or storage key that cannot be
+	// encoded.
+	ErrInvalidKey = errors.New("invalid key")
+)
@@ -0,0 +1,53 @@
+package state
+
+import (
+	"testing"
+
+	ics23 "github.com/confio/ics23/go"
+)
+
+// leafOp returns the leaf of a single-leaf IAVL tree at version 1, or of a
+// simple Merkle tree if simple is set.
+func leafOp(simple bool) *ics23.LeafOp {
+	op := &ics23.LeafOp{
+		Hash:         ics23.HashOp_SHA256,
+		PrehashKey:   ics23.HashOp_NO_HASH,
+		PrehashValue: ics23.HashOp_SHA256,
+		Length:       ics23.LengthOp_VAR_PROTO,
+		// height 0, size 1, version 1, zigzag varints
+		Prefix: []byte{0, 2, 2},
+	}
+	if simple {
+		op.Prefix = []byte{0}
+	}
+	return op
+}
+
+// existenceProof proves key = value in a tree holding only that key, and
+// returns the tree's root.
+func existenceProof(t *testing.T, key, value []byte, simple bool) (*ics23.CommitmentProof, []byte) {
+	t.Helper()
+	exist := &ics23.ExistenceProof{Key: key, Value: value, Leaf: leafOp(simple)}
+	proof := &ics23.CommitmentProof{Proof: &ics23.CommitmentProof_Exist{Exist: exist}}
+	root, err := proof.Calculate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proof, root
+}
+
+// storeProof returns a proof of key = value in storeName, whose IAVL tree
+// holds only that key in a multistore holding only that store, and the app
+// hash it verifies against.
+func storeProof(t *testing.T, storeName string, key, value []byte) (*StoreProof, []byte) {
+	t.Helper()
+	iavlProof, storeRoot := existenceProof(t, key, value, false)
+	multiStoreProof, appHash := existenceProof(t, []byte(storeName), storeRoot, true)
+	return &StoreProof{
+		StoreName:       storeName,
+		Key:             key,
+		Value:           value,
+		IAVLProof:       iavlProof,
+		MultiStoreProof: multiStoreProof,
+	}, appHash
+}
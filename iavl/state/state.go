@@ -0,0 +1,108 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+
+	ics23 "github.com/confio/ics23/go"
+	tmcrypto "github.com/tendermint/tendermint/proto/tendermint/crypto"
+	"github.com/tendermint/tendermint/types"
+
+	"server/headerTest/header"
+)
+
+// Proof op types returned by a Cosmos SDK ABCI query with prove=true.
+const (
+	ProofOpIAVLCommitment   = "ics23:iavl"
+	ProofOpSimpleCommitment = "ics23:simple"
+)
+
+// StoreProof proves the value of a key in one store of a Cosmos SDK
+// multistore: the key against the store's IAVL root, then the store root
+// against the AppHash. A nil Value proves that the key does not exist.
+type StoreProof struct {
+	StoreName       string                 `json:"store_name"`
+	Key             []byte                 `json:"key"`
+	Value           []byte                 `json:"value,omitempty"`
+	IAVLProof       *ics23.CommitmentProof `json:"iavl_proof"`
+	MultiStoreProof *ics23.CommitmentProof `json:"multistore_proof"`
+}
+
+// ProofFromOps builds a StoreProof from the proof ops of an ABCI query for
+// key in storeName. value is the query's value, empty if the key is absent.
+func ProofFromOps(ops *tmcrypto.ProofOps, key, value []byte) (*StoreProof, error) {
+	if ops == nil || len(ops.Ops) != 2 {
+		return nil, fmt.Errorf("%w: expected an iavl and a multistore proof op", ErrMalformedProof)
+	}
+	iavlOp, storeOp := ops.Ops[0], ops.Ops[1]
+	if iavlOp.Type != ProofOpIAVLCommitment {
+		return nil, fmt.Errorf("%w: unexpected proof op %q, expected %q", ErrMalformedProof, iavlOp.Type, ProofOpIAVLCommitment)
+	}
+	if storeOp.Type != ProofOpSimpleCommitment {
+		return nil, fmt.Errorf("%w: unexpected proof op %q, expected %q", ErrMalformedProof, storeOp.Type, ProofOpSimpleCommitment)
+	}
+	if !bytes.Equal(iavlOp.Key, key) {
+		return nil, fmt.Errorf("%w: proof is for key %X, expected %X", ErrKeyMismatch, iavlOp.Key, key)
+	}
+
+	proof := &StoreProof{
+		StoreName:       string(storeOp.Key),
+		Key:             key,
+		IAVLProof:       new(ics23.CommitmentProof),
+		MultiStoreProof: new(ics23.CommitmentProof),
+	}
+	if len(value) > 0 {
+		proof.Value = value
+	}
+	if err := proof.IAVLProof.Unmarshal(iavlOp.Data); err != nil {
+		return nil, fmt.Errorf("%w: decode iavl proof: %v", ErrMalformedProof, err)
+	}
+	if err := proof.MultiStoreProof.Unmarshal(storeOp.Data); err != nil {
+		return nil, fmt.Errorf("%w: decode multistore proof: %v", ErrMalformedProof, err)
+	}
+	return proof, nil
+}
+
+// Exists reports whether the proof is an existence proof.
+func (p *StoreProof) Exists() bool {
+	return p.Value != nil
+}
+
+// Verify checks the proof against appHash.
+func (p *StoreProof) Verify(appHash []byte) error {
+	if p.IAVLProof == nil || p.MultiStoreProof == nil {
+		return fmt.Errorf("%w: incomplete store proof", ErrMalformedProof)
+	}
+
+	storeRoot, err := p.IAVLProof.Calculate()
+	if err != nil {
+		return fmt.Errorf("%w: calculate %s store root: %v", ErrInvalidProof, p.StoreName, err)
+	}
+
+	if p.Exists() {
+		if !ics23.VerifyMembership(ics23.IavlSpec, storeRoot, p.IAVLProof, p.Key, p.Value) {
+			return fmt.Errorf("%w: key %X does not have the given value in store %s", ErrInvalidProof, p.Key, p.StoreName)
+		}
+	} else if !ics23.VerifyNonMembership(ics23.IavlSpec, storeRoot, p.IAVLProof, p.Key) {
+		return fmt.Errorf("%w: key %X is not proven absent from store %s", ErrInvalidProof, p.Key, p.StoreName)
+	}
+
+	if !ics23.VerifyMembership(ics23.TendermintSpec, appHash, p.MultiStoreProof, []byte(p.StoreName), storeRoot) {
+		return fmt.Errorf("%w: store %s root %X is not committed to app hash %X", ErrInvalidProof, p.StoreName, storeRoot, appHash)
+	}
+	return nil
+}
+
+// VerifyAgainstHeader checks the proof of state at queryHeight against the
+// AppHash of h. The AppHash of a header commits to the state after the
+// previous block, so h must be the header at queryHeight+1.
+func (p *StoreProof) VerifyAgainstHeader(h *types.Header, queryHeight int64) error {
+	if h == nil {
+		return fmt.Errorf("%w: nil header", header.ErrInvalidHeader)
+	}
+	if h.Height != queryHeight+1 {
+		return fmt.Errorf("%w: state at height %d is committed to header %d, got header %d",
+			ErrHeightMismatch, queryHeight, queryHeight+1, h.Height)
+	}
+	return p.Verify(h.AppHash)
+}
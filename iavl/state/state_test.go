@@ -0,0 +1,127 @@
+package state
+
+import (
+	"errors"
+	"testing"
+
+	ics23 "github.com/confio/ics23/go"
+	tmcrypto "github.com/tendermint/tendermint/proto/tendermint/crypto"
+	"github.com/tendermint/tendermint/types"
+
+	"server/headerTest/header"
+)
+
+func TestStoreProofVerify(t *testing.T) {
+	proof, appHash := storeProof(t, "bank", []byte("key"), []byte("value"))
+	if err := proof.Verify(appHash); err != nil {
+		t.Fatal(err)
+	}
+
+	wrongValue := *proof
+	wrongValue.Value = []byte("other")
+	wrongStore := *proof
+	wrongStore.StoreName = "staking"
+	incomplete := *proof
+	incomplete.MultiStoreProof = nil
+
+	tests := []struct {
+		name    string
+		proof   *StoreProof
+		appHash []byte
+		want    error
+	}{
+		{"other value", &wrongValue, appHash, ErrInvalidProof},
+		{"other store", &wrongStore, appHash, ErrInvalidProof},
+		{"other app hash", proof, make([]byte, len(appHash)), ErrInvalidProof},
+		{"incomplete", &incomplete, appHash, ErrMalformedProof},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.proof.Verify(tc.appHash); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestStoreProofNonExistence(t *testing.T) {
+	// the tree holds only "a", so "z" is right of its rightmost key
+	left, storeRoot := existenceProof(t, []byte("a"), []byte("value"), false)
+	multiStoreProof, appHash := existenceProof(t, []byte("bank"), storeRoot, true)
+	proof := &StoreProof{
+		StoreName: "bank",
+		Key:       []byte("z"),
+		IAVLProof: &ics23.CommitmentProof{Proof: &ics23.CommitmentProof_Nonexist{
+			Nonexist: &ics23.NonExistenceProof{Key: []byte("z"), Left: left.GetExist()},
+		}},
+		MultiStoreProof: multiStoreProof,
+	}
+	if err := proof.Verify(appHash); err != nil {
+		t.Fatal(err)
+	}
+
+	// a proof of absence does not prove a value
+	proof.Value = []byte("value")
+	if err := proof.Verify(appHash); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidProof)
+	}
+}
+
+func TestStoreProofVerifyAgainstHeader(t *testing.T) {
+	proof, appHash := storeProof(t, "bank", []byte("key"), []byte("value"))
+
+	if err := proof.VerifyAgainstHeader(&types.Header{Height: 11, AppHash: appHash}, 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := proof.VerifyAgainstHeader(&types.Header{Height: 10, AppHash: appHash}, 10); !errors.Is(err, ErrHeightMismatch) {
+		t.Errorf("header at the query height: got %v, want %v", err, ErrHeightMismatch)
+	}
+	if err := proof.VerifyAgainstHeader(nil, 10); !errors.Is(err, header.ErrInvalidHeader) {
+		t.Errorf("nil header: got %v, want %v", err, header.ErrInvalidHeader)
+	}
+}
+
+func TestProofFromOps(t *testing.T) {
+	proof, appHash := storeProof(t, "bank", []byte("key"), []byte("value"))
+	iavlData, err := proof.IAVLProof.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	storeData, err := proof.MultiStoreProof.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops := func(iavlType string, data []byte) *tmcrypto.ProofOps {
+		return &tmcrypto.ProofOps{Ops: []tmcrypto.ProofOp{
+			{Type: iavlType, Key: []byte("key"), Data: data},
+			{Type: ProofOpSimpleCommitment, Key: []byte("bank"), Data: storeData},
+		}}
+	}
+
+	got, err := ProofFromOps(ops(ProofOpIAVLCommitment, iavlData), []byte("key"), []byte("value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := got.Verify(appHash); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		ops  *tmcrypto.ProofOps
+		key  []byte
+		want error
+	}{
+		{"no ops", nil, []byte("key"), ErrMalformedProof},
+		{"unknown op", ops("ics23:smt", iavlData), []byte("key"), ErrMalformedProof},
+		{"undecodable proof", ops(ProofOpIAVLCommitment, []byte{0xff}), []byte("key"), ErrMalformedProof},
+		{"other key", ops(ProofOpIAVLCommitment, iavlData), []byte("other"), ErrKeyMismatch},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ProofFromOps(tc.ops, tc.key, []byte("value")); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
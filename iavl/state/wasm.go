@@ -0,0 +1,149 @@
+package state
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/decred/dcrd/bech32"
+)
+
+// WasmStoreName is the name of the CosmWasm module store.
+const WasmStoreName = "wasm"
+
+// ContractStorePrefix prefixes the storage of every contract in the wasm
+// store.
+var ContractStorePrefix = []byte{0x03}
+
+// ContractAddress decodes a bech32 contract address such as orai1...
+func ContractAddress(addr string) ([]byte, error) {
+	_, bz, err := bech32.DecodeToBase256(addr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: decode contract address %q: %v", ErrInvalidKey, addr, err)
+	}
+	return bz, nil
+}
+
+// ContractStoreKey returns the key of a contract storage key in the wasm
+// store: 0x03 || contract || key.
+func ContractStoreKey(contract, key []byte) []byte {
+	storeKey := make([]byte, 0, len(ContractStorePrefix)+len(contract)+len(key))
+	storeKey = append(storeKey, ContractStorePrefix...)
+	storeKey = append(storeKey, contract...)
+	return append(storeKey, key...)
+}
+
+// ItemKey returns the storage key of a cw-storage-plus Item, which is its
+// namespace.
+func ItemKey(namespace string) []byte {
+	return []byte(namespace)
+}
+
+// MapKey returns the storage key of an entry of a cw-storage-plus Map. The
+// namespace and every key element but the last are prefixed with their
+// 2-byte big-endian length; the last element is appended as is.
+func MapKey(namespace string, keys ...[]byte) ([]byte, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: map key needs at least one element", ErrInvalidKey)
+	}
+
+	var buf bytes.Buffer
+	for _, elem := range append([][]byte{[]byte(namespace)}, keys[:len(keys)-1]...) {
+		if len(elem) > 0xFFFF {
+			return nil, fmt.Errorf("%w: map key element of %d bytes is too long", ErrInvalidKey, len(elem))
+		}
+		var length [2]byte
+		binary.BigEndian.PutUint16(length[:], uint16(len(elem)))
+		buf.Write(length[:])
+		buf.Write(elem)
+	}
+	buf.Write(keys[len(keys)-1])
+	return buf.Bytes(), nil
+}
+
+// KeyElement encodes a cw-storage-plus key element given as text: "string",
+// the default, as UTF-8; "u8" to "u128" as big-endian integers; "i8" to "i64"
+// as big-endian integers with the sign bit flipped, so they sort in order;
+// "hex" and "base64" as the raw bytes they decode to; and "bech32" as the
+// address bytes, as for a CanonicalAddr key.
+func KeyElement(typ, value string) ([]byte, error) {
+	switch typ {
+	case "", "string":
+		return []byte(value), nil
+	case "u8", "u16", "u32", "u64":
+		bits, _ := strconv.Atoi(typ[1:])
+		n, err := strconv.ParseUint(value, 10, bits)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s key element: %v", ErrInvalidKey, typ, err)
+		}
+		return bigEndian(n, bits/8), nil
+	case "u128":
+		n, ok := new(big.Int).SetString(value, 10)
+		if !ok || n.Sign() < 0 || n.BitLen() > 128 {
+			return nil, fmt.Errorf("%w: u128 key element: invalid value %q", ErrInvalidKey, value)
+		}
+		return n.FillBytes(make([]byte, 16)), nil
+	case "i8", "i16", "i32", "i64":
+		bits, _ := strconv.Atoi(typ[1:])
+		n, err := strconv.ParseInt(value, 10, bits)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s key element: %v", ErrInvalidKey, typ, err)
+		}
+		return bigEndian(uint64(n)^1<<(bits-1), bits/8), nil
+	case "hex":
+		bz, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: hex key element: %v", ErrInvalidKey, err)
+		}
+		return bz, nil
+	case "base64":
+		bz, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: base64 key element: %v", ErrInvalidKey, err)
+		}
+		return bz, nil
+	case "bech32":
+		_, bz, err := bech32.DecodeToBase256(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bech32 key element: %v", ErrInvalidKey, err)
+		}
+		return bz, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown key element type %q", ErrInvalidKey, typ)
+	}
+}
+
+// bigEndian returns the size low bytes of n in big-endian order.
+func bigEndian(n uint64, size int) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], n)
+	return buf[8-size:]
+}
+
+// CheckContractKey returns an error if proof is not about key in the
+// storage of contract.
+func CheckContractKey(proof *StoreProof, contract, key []byte) error {
+	if proof == nil {
+		return fmt.Errorf("%w: nil store proof", ErrMalformedProof)
+	}
+	if proof.StoreName != WasmStoreName {
+		return fmt.Errorf("%w: proof is for store %s, expected %s", ErrKeyMismatch, proof.StoreName, WasmStoreName)
+	}
+	if storeKey := ContractStoreKey(contract, key); !bytes.Equal(proof.Key, storeKey) {
+		return fmt.Errorf("%w: proof is for key %X, expected contract storage key %X", ErrKeyMismatch, proof.Key, storeKey)
+	}
+	return nil
+}
+
+// VerifyContractState checks that proof proves the value of key in the
+// storage of contract against appHash.
+func VerifyContractState(proof *StoreProof, contract, key, appHash []byte) error {
+	if err := CheckContractKey(proof, contract, key); err != nil {
+		return err
+	}
+	return proof.Verify(appHash)
+}
@@ -0,0 +1,121 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/bech32"
+)
+
+func TestMapKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		keys      [][]byte
+		want      []byte
+	}{
+		{"single key", "balances", [][]byte{[]byte("abc")}, []byte("\x00\x08balancesabc")},
+		{"composite key", "n", [][]byte{[]byte("a"), []byte("b")}, []byte("\x00\x01n\x00\x01ab")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := MapKey(tc.namespace, tc.keys...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("got %X, want %X", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := MapKey("balances"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("no key: got %v, want %v", err, ErrInvalidKey)
+	}
+	if _, err := MapKey("balances", make([]byte, 0x10000), nil); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("long key: got %v, want %v", err, ErrInvalidKey)
+	}
+}
+
+func TestKeyElement(t *testing.T) {
+	addr := bytes.Repeat([]byte{0xAB}, 20)
+	bech, err := bech32.EncodeFromBase256("orai", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		typ   string
+		value string
+		want  []byte
+	}{
+		{"", "abc", []byte("abc")},
+		{"u8", "255", []byte{0xFF}},
+		{"u64", "1", []byte{0, 0, 0, 0, 0, 0, 0, 1}},
+		{"u128", "1", append(make([]byte, 15), 1)},
+		{"i8", "0", []byte{0x80}},
+		{"i32", "-1", []byte{0x7F, 0xFF, 0xFF, 0xFF}},
+		{"hex", "0aff", []byte{0x0A, 0xFF}},
+		{"base64", "AQI=", []byte{1, 2}},
+		{"bech32", bech, addr},
+	}
+	for _, tc := range tests {
+		t.Run(tc.typ+" "+tc.value, func(t *testing.T) {
+			got, err := KeyElement(tc.typ, tc.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("got %X, want %X", got, tc.want)
+			}
+		})
+	}
+
+	for _, tc := range []struct{ typ, value string }{
+		{"u8", "256"},
+		{"u128", "-1"},
+		{"i8", "128"},
+		{"hex", "xyz"},
+		{"float", "1.5"},
+	} {
+		if _, err := KeyElement(tc.typ, tc.value); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("%s %q: got %v, want %v", tc.typ, tc.value, err, ErrInvalidKey)
+		}
+	}
+}
+
+func TestVerifyContractState(t *testing.T) {
+	contract := bytes.Repeat([]byte{0x01}, 32)
+	key, err := MapKey("balances", []byte("alice"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, appHash := storeProof(t, WasmStoreName, ContractStoreKey(contract, key), []byte(`"100"`))
+	if err := VerifyContractState(proof, contract, key, appHash); err != nil {
+		t.Fatal(err)
+	}
+
+	otherStore, otherAppHash := storeProof(t, "bank", ContractStoreKey(contract, key), []byte(`"100"`))
+	tests := []struct {
+		name     string
+		proof    *StoreProof
+		appHash  []byte
+		contract []byte
+		key      []byte
+		want     error
+	}{
+		{"nil proof", nil, appHash, contract, key, ErrMalformedProof},
+		{"other store", otherStore, otherAppHash, contract, key, ErrKeyMismatch},
+		{"other contract", proof, appHash, bytes.Repeat([]byte{0x02}, 32), key, ErrKeyMismatch},
+		{"other key", proof, appHash, contract, ItemKey("config"), ErrKeyMismatch},
+		{"other app hash", proof, make([]byte, len(appHash)), contract, key, ErrInvalidProof},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := VerifyContractState(tc.proof, tc.contract, tc.key, tc.appHash); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
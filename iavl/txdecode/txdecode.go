@@ -0,0 +1,425 @@
+package txdecode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/types"
+)
+
+// Message type URLs with a decoder.
+const (
+	TypeMsgExecuteContract = "/cosmwasm.wasm.v1.MsgExecuteContract"
+	TypeMsgSend            = "/cosmos.bank.v1beta1.MsgSend"
+	TypeMsgTransfer        = "/ibc.applications.transfer.v1.MsgTransfer"
+)
+
+// Tx is a decoded Cosmos SDK TxRaw.
+type Tx struct {
+	Hash       tmbytes.HexBytes `json:"hash"`
+	Body       Body             `json:"body"`
+	AuthInfo   AuthInfo         `json:"auth_info"`
+	Signatures [][]byte         `json:"signatures"`
+}
+
+// Body is a decoded cosmos.tx.v1beta1.TxBody.
+type Body struct {
+	Messages      []Message `json:"messages"`
+	Memo          string    `json:"memo,omitempty"`
+	TimeoutHeight uint64    `json:"timeout_height,omitempty"`
+}
+
+// Message is a tx message. Value is the decoded message for the known type
+// URLs, and the raw protobuf bytes otherwise.
+type Message struct {
+	TypeURL string      `json:"@type"`
+	Value   interface{} `json:"value"`
+}
+
+// AuthInfo is a decoded cosmos.tx.v1beta1.AuthInfo.
+type AuthInfo struct {
+	SignerInfos []SignerInfo `json:"signer_infos"`
+	Fee         Fee          `json:"fee"`
+}
+
+// SignerInfo is a decoded cosmos.tx.v1beta1.SignerInfo.
+type SignerInfo struct {
+	PublicKey *PubKey `json:"public_key,omitempty"`
+	SignMode  uint64  `json:"sign_mode"`
+	Sequence  uint64  `json:"sequence"`
+}
+
+// PubKey is a signer public key.
+type PubKey struct {
+	TypeURL string `json:"@type"`
+	Key     []byte `json:"key"`
+}
+
+// Fee is a decoded cosmos.tx.v1beta1.Fee.
+type Fee struct {
+	Amount   []Coin `json:"amount"`
+	GasLimit uint64 `json:"gas_limit"`
+	Payer    string `json:"payer,omitempty"`
+	Granter  string `json:"granter,omitempty"`
+}
+
+// Coin is a decoded cosmos.base.v1beta1.Coin.
+type Coin struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
+// MsgExecuteContract is a decoded /cosmwasm.wasm.v1.MsgExecuteContract.
+type MsgExecuteContract struct {
+	Sender   string          `json:"sender"`
+	Contract string          `json:"contract"`
+	Msg      json.RawMessage `json:"msg"`
+	Funds    []Coin          `json:"funds"`
+}
+
+// MsgSend is a decoded /cosmos.bank.v1beta1.MsgSend.
+type MsgSend struct {
+	FromAddress string `json:"from_address"`
+	ToAddress   string `json:"to_address"`
+	Amount      []Coin `json:"amount"`
+}
+
+// Height is a decoded ibc.core.client.v1.Height.
+type Height struct {
+	RevisionNumber uint64 `json:"revision_number"`
+	RevisionHeight uint64 `json:"revision_height"`
+}
+
+// MsgTransfer is a decoded /ibc.applications.transfer.v1.MsgTransfer.
+type MsgTransfer struct {
+	SourcePort       string `json:"source_port"`
+	SourceChannel    string `json:"source_channel"`
+	Token            Coin   `json:"token"`
+	Sender           string `json:"sender"`
+	Receiver         string `json:"receiver"`
+	TimeoutHeight    Height `json:"timeout_height"`
+	TimeoutTimestamp uint64 `json:"timeout_timestamp"`
+	Memo             string `json:"memo,omitempty"`
+}
+
+// Decode decodes the bytes of a Cosmos SDK TxRaw, as included in a block.
+func Decode(tx types.Tx) (*Tx, error) {
+	fs, err := fields(tx)
+	if err != nil {
+		return nil, fmt.Errorf("decode tx raw: %w", err)
+	}
+
+	res := &Tx{Hash: tx.Hash()}
+	for _, f := range fs {
+		switch f.Num {
+		case 1:
+			if res.Body, err = decodeBody(f.Bytes); err != nil {
+				return nil, fmt.Errorf("decode tx body: %w", err)
+			}
+		case 2:
+			if res.AuthInfo, err = decodeAuthInfo(f.Bytes); err != nil {
+				return nil, fmt.Errorf("decode auth info: %w", err)
+			}
+		case 3:
+			res.Signatures = append(res.Signatures, f.Bytes)
+		}
+	}
+	return res, nil
+}
+
+func decodeBody(b []byte) (Body, error) {
+	var body Body
+	fs, err := fields(b)
+	if err != nil {
+		return body, err
+	}
+	for _, f := range fs {
+		switch f.Num {
+		case 1:
+			msg, err := decodeMessage(f.Bytes)
+			if err != nil {
+				return body, fmt.Errorf("message #%d: %w", len(body.Messages), err)
+			}
+			body.Messages = append(body.Messages, msg)
+		case 2:
+			body.Memo = string(f.Bytes)
+		case 3:
+			body.TimeoutHeight = f.Varint
+		}
+	}
+	return body, nil
+}
+
+// decodeAny returns the type URL and value of a google.protobuf.Any.
+func decodeAny(b []byte) (typeURL string, value []byte, err error) {
+	fs, err := fields(b)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, f := range fs {
+		switch f.Num {
+		case 1:
+			typeURL = string(f.Bytes)
+		case 2:
+			value = f.Bytes
+		}
+	}
+	return typeURL, value, nil
+}
+
+func decodeMessage(b []byte) (Message, error) {
+	typeURL, value, err := decodeAny(b)
+	if err != nil {
+		return Message{}, err
+	}
+
+	msg := Message{TypeURL: typeURL}
+	switch typeURL {
+	case TypeMsgExecuteContract:
+		msg.Value, err = decodeMsgExecuteContract(value)
+	case TypeMsgSend:
+		msg.Value, err = decodeMsgSend(value)
+	case TypeMsgTransfer:
+		msg.Value, err = decodeMsgTransfer(value)
+	default:
+		msg.Value = value
+	}
+	if err != nil {
+		return Message{}, fmt.Errorf("decode %s: %w", typeURL, err)
+	}
+	return msg, nil
+}
+
+func decodeCoin(b []byte) (Coin, error) {
+	var coin Coin
+	fs, err := fields(b)
+	if err != nil {
+		return coin, err
+	}
+	for _, f := range fs {
+		switch f.Num {
+		case 1:
+			coin.Denom = string(f.Bytes)
+		case 2:
+			coin.Amount = string(f.Bytes)
+		}
+	}
+	return coin, nil
+}
+
+func decodeMsgExecuteContract(b []byte) (*MsgExecuteContract, error) {
+	msg := new(MsgExecuteContract)
+	fs, err := fields(b)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fs {
+		switch f.Num {
+		case 1:
+			msg.Sender = string(f.Bytes)
+		case 2:
+			msg.Contract = string(f.Bytes)
+		case 3:
+			if !json.Valid(f.Bytes) {
+				return nil, fmt.Errorf("%w: execute msg is not valid JSON", ErrMalformedTx)
+			}
+			msg.Msg = f.Bytes
+		case 5:
+			coin, err := decodeCoin(f.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			msg.Funds = append(msg.Funds, coin)
+		}
+	}
+	return msg, nil
+}
+
+func decodeMsgSend(b []byte) (*MsgSend, error) {
+	msg := new(MsgSend)
+	fs, err := fields(b)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fs {
+		switch f.Num {
+		case 1:
+			msg.FromAddress = string(f.Bytes)
+		case 2:
+			msg.ToAddress = string(f.Bytes)
+		case 3:
+			coin, err := decodeCoin(f.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			msg.Amount = append(msg.Amount, coin)
+		}
+	}
+	return msg, nil
+}
+
+func decodeMsgTransfer(b []byte) (*MsgTransfer, error) {
+	msg := new(MsgTransfer)
+	fs, err := fields(b)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fs {
+		switch f.Num {
+		case 1:
+			msg.SourcePort = string(f.Bytes)
+		case 2:
+			msg.SourceChannel = string(f.Bytes)
+		case 3:
+			if msg.Token, err = decodeCoin(f.Bytes); err != nil {
+				return nil, err
+			}
+		case 4:
+			msg.Sender = string(f.Bytes)
+		case 5:
+			msg.Receiver = string(f.Bytes)
+		case 6:
+			if msg.TimeoutHeight, err = decodeHeight(f.Bytes); err != nil {
+				return nil, err
+			}
+		case 7:
+			msg.TimeoutTimestamp = f.Varint
+		case 8:
+			msg.Memo = string(f.Bytes)
+		}
+	}
+	return msg, nil
+}
+
+func decodeHeight(b []byte) (Height, error) {
+	var h Height
+	fs, err := fields(b)
+	if err != nil {
+		return h, err
+	}
+	for _, f := range fs {
+		switch f.Num {
+		case 1:
+			h.RevisionNumber = f.Varint
+		case 2:
+			h.RevisionHeight = f.Varint
+		}
+	}
+	return h, nil
+}
+
+func decodeAuthInfo(b []byte) (AuthInfo, error) {
+	var info AuthInfo
+	fs, err := fields(b)
+	if err != nil {
+		return info, err
+	}
+	for _, f := range fs {
+		switch f.Num {
+		case 1:
+			signer, err := decodeSignerInfo(f.Bytes)
+			if err != nil {
+				return info, err
+			}
+			info.SignerInfos = append(info.SignerInfos, signer)
+		case 2:
+			if info.Fee, err = decodeFee(f.Bytes); err != nil {
+				return info, err
+			}
+		}
+	}
+	return info, nil
+}
+
+func decodeSignerInfo(b []byte) (SignerInfo, error) {
+	var signer SignerInfo
+	fs, err := fields(b)
+	if err != nil {
+		return signer, err
+	}
+	for _, f := range fs {
+		switch f.Num {
+		case 1:
+			if signer.PublicKey, err = decodePubKey(f.Bytes); err != nil {
+				return signer, err
+			}
+		case 2:
+			if signer.SignMode, err = decodeSignMode(f.Bytes); err != nil {
+				return signer, err
+			}
+		case 3:
+			signer.Sequence = f.Varint
+		}
+	}
+	return signer, nil
+}
+
+// decodePubKey decodes a single-key Any, such as
+// /cosmos.crypto.secp256k1.PubKey, whose key is field 1.
+func decodePubKey(b []byte) (*PubKey, error) {
+	typeURL, value, err := decodeAny(b)
+	if err != nil {
+		return nil, err
+	}
+	pk := &PubKey{TypeURL: typeURL}
+	fs, err := fields(value)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fs {
+		if f.Num == 1 {
+			pk.Key = f.Bytes
+		}
+	}
+	return pk, nil
+}
+
+// decodeSignMode returns the mode of a ModeInfo.Single; multisig mode infos
+// return 0.
+func decodeSignMode(b []byte) (uint64, error) {
+	fs, err := fields(b)
+	if err != nil {
+		return 0, err
+	}
+	for _, f := range fs {
+		if f.Num != 1 {
+			continue
+		}
+		single, err := fields(f.Bytes)
+		if err != nil {
+			return 0, err
+		}
+		for _, sf := range single {
+			if sf.Num == 1 {
+				return sf.Varint, nil
+			}
+		}
+	}
+	return 0, nil
+}
+
+func decodeFee(b []byte) (Fee, error) {
+	var fee Fee
+	fs, err := fields(b)
+	if err != nil {
+		return fee, err
+	}
+	for _, f := range fs {
+		switch f.Num {
+		case 1:
+			coin, err := decodeCoin(f.Bytes)
+			if err != nil {
+				return fee, err
+			}
+			fee.Amount = append(fee.Amount, coin)
+		case 2:
+			fee.GasLimit = f.Varint
+		case 3:
+			fee.Payer = string(f.Bytes)
+		case 4:
+			fee.Granter = string(f.Bytes)
+		}
+	}
+	return fee, nil
+}
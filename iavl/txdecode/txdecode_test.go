@@ -0,0 +1,108 @@
+package txdecode
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/tendermint/tendermint/types"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// message encodes fields, given as alternating field numbers and values, as
+// a protobuf message. Values are strings or byte slices, encoded as bytes,
+// or uint64s, encoded as varints.
+func message(t *testing.T, fields ...interface{}) []byte {
+	t.Helper()
+	var b []byte
+	for i := 0; i < len(fields); i += 2 {
+		num := protowire.Number(fields[i].(int))
+		switch v := fields[i+1].(type) {
+		case string:
+			b = protowire.AppendTag(b, num, protowire.BytesType)
+			b = protowire.AppendString(b, v)
+		case []byte:
+			b = protowire.AppendTag(b, num, protowire.BytesType)
+			b = protowire.AppendBytes(b, v)
+		case uint64:
+			b = protowire.AppendTag(b, num, protowire.VarintType)
+			b = protowire.AppendVarint(b, v)
+		default:
+			t.Fatalf("field %d: unsupported value %T", num, v)
+		}
+	}
+	return b
+}
+
+func TestDecode(t *testing.T) {
+	coin := message(t, 1, "orai", 2, "100")
+	execute := message(t, 1, "orai1sender", 2, "orai1contract", 3, `{"transfer":{}}`, 5, coin)
+	send := message(t, 1, "orai1from", 2, "orai1to", 3, coin)
+	body := message(t,
+		1, message(t, 1, TypeMsgExecuteContract, 2, execute),
+		1, message(t, 1, TypeMsgSend, 2, send),
+		1, message(t, 1, "/cosmos.gov.v1beta1.MsgVote", 2, []byte{0x08, 0x01}),
+		2, "memo",
+		3, uint64(42),
+	)
+	pubKey := message(t, 1, "/cosmos.crypto.secp256k1.PubKey", 2, message(t, 1, []byte{0x02, 0x03}))
+	signer := message(t, 1, pubKey, 2, message(t, 1, message(t, 1, uint64(1))), 3, uint64(7))
+	authInfo := message(t, 1, signer, 2, message(t, 1, coin, 2, uint64(200000)))
+	raw := types.Tx(message(t, 1, body, 2, authInfo, 3, []byte("sig")))
+
+	tx, err := Decode(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(tx.Hash, raw.Hash()) {
+		t.Errorf("hash %X, want %X", tx.Hash, raw.Hash())
+	}
+	if tx.Body.Memo != "memo" || tx.Body.TimeoutHeight != 42 {
+		t.Errorf("memo %q, timeout height %d, want %q, 42", tx.Body.Memo, tx.Body.TimeoutHeight, "memo")
+	}
+	if len(tx.Body.Messages) != 3 {
+		t.Fatalf("got %d messages, want 3", len(tx.Body.Messages))
+	}
+	exec, ok := tx.Body.Messages[0].Value.(*MsgExecuteContract)
+	if !ok || exec.Contract != "orai1contract" || string(exec.Msg) != `{"transfer":{}}` || len(exec.Funds) != 1 {
+		t.Errorf("execute message %+v", tx.Body.Messages[0].Value)
+	}
+	if msg, ok := tx.Body.Messages[1].Value.(*MsgSend); !ok || msg.ToAddress != "orai1to" || msg.Amount[0] != (Coin{"orai", "100"}) {
+		t.Errorf("send message %+v", tx.Body.Messages[1].Value)
+	}
+	if raw, ok := tx.Body.Messages[2].Value.([]byte); !ok || !bytes.Equal(raw, []byte{0x08, 0x01}) {
+		t.Errorf("unknown message %+v, want its raw bytes", tx.Body.Messages[2].Value)
+	}
+	if len(tx.AuthInfo.SignerInfos) != 1 {
+		t.Fatalf("got %d signers, want 1", len(tx.AuthInfo.SignerInfos))
+	}
+	if s := tx.AuthInfo.SignerInfos[0]; s.SignMode != 1 || s.Sequence != 7 || !bytes.Equal(s.PublicKey.Key, []byte{0x02, 0x03}) {
+		t.Errorf("signer %+v", s)
+	}
+	if tx.AuthInfo.Fee.GasLimit != 200000 {
+		t.Errorf("gas limit %d, want 200000", tx.AuthInfo.Fee.GasLimit)
+	}
+	if len(tx.Signatures) != 1 || string(tx.Signatures[0]) != "sig" {
+		t.Errorf("signatures %q", tx.Signatures)
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	badExecute := message(t, 2, "orai1contract", 3, "{not json")
+	tests := []struct {
+		name string
+		tx   types.Tx
+	}{
+		{"invalid tag", types.Tx{0xFF}},
+		{"truncated field", types.Tx{0x0A, 0x05, 0x01}},
+		{"invalid body", types.Tx(message(t, 1, []byte{0x0A, 0x05}))},
+		{"execute msg not JSON", types.Tx(message(t, 1, message(t, 1, message(t, 1, TypeMsgExecuteContract, 2, badExecute))))},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Decode(tc.tx); !errors.Is(err, ErrMalformedTx) {
+				t.Fatalf("got %v, want %v", err, ErrMalformedTx)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package txdecode
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ErrMalformedTx is a transaction, or part of one, that is not valid
+// protobuf or holds an invalid value.
+var ErrMalformedTx = errors.New("malformed transaction")
+
+// field is one decoded protobuf field. Bytes holds length-delimited values,
+// Varint holds varint values.
+type field struct {
+	Num    protowire.Number
+	Bytes  []byte
+	Varint uint64
+}
+
+// fields splits a protobuf message into its fields, in wire order.
+func fields(b []byte) ([]field, error) {
+	var fs []field
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, fmt.Errorf("%w: invalid field tag: %v", ErrMalformedTx, protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		f := field{Num: num}
+		switch typ {
+		case protowire.VarintType:
+			f.Varint, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			f.Bytes, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("%w: invalid field %d: %v", ErrMalformedTx, num, protowire.ParseError(n))
+		}
+		b = b[n:]
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
@@ -0,0 +1,19 @@
+package txproof
+
+import "errors"
+
+// Errors returned, wrapped, by this package, so callers can classify them
+// with errors.Is. A nil header is reported with header.ErrInvalidHeader and a
+// block hash proof that does not verify with header.ErrInvalidProof.
+var (
+	// ErrIndexOutOfRange is a tx or result index outside the block.
+	ErrIndexOutOfRange = errors.New("index out of range")
+	// ErrInvalidResult is a nil or unencodable tx result.
+	ErrInvalidResult = errors.New("invalid tx result")
+	// ErrRootMismatch is a set of txs or results whose Merkle root is not
+	// the one in the header.
+	ErrRootMismatch = errors.New("root does not match header")
+	// ErrInvalidProof is an inclusion proof that is missing or does not
+	// verify.
+	ErrInvalidProof = errors.New("invalid inclusion proof")
+)
@@ -0,0 +1,142 @@
+package txproof
+
+import (
+	"bytes"
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/types"
+
+	"server/headerTest/header"
+)
+
+// DeliverTxResult holds the deterministic fields of a ResponseDeliverTx,
+// which are the ones committed to by LastResultsHash.
+type DeliverTxResult struct {
+	Code      uint32 `json:"code"`
+	Data      []byte `json:"data,omitempty"`
+	GasWanted int64  `json:"gas_wanted"`
+	GasUsed   int64  `json:"gas_used"`
+}
+
+// NewDeliverTxResult strips the non-deterministic fields from res.
+func NewDeliverTxResult(res *abci.ResponseDeliverTx) DeliverTxResult {
+	return DeliverTxResult{
+		Code:      res.Code,
+		Data:      res.Data,
+		GasWanted: res.GasWanted,
+		GasUsed:   res.GasUsed,
+	}
+}
+
+// Bytes returns the leaf encoding of the result.
+func (r DeliverTxResult) Bytes() ([]byte, error) {
+	res := abci.ResponseDeliverTx{
+		Code:      r.Code,
+		Data:      r.Data,
+		GasWanted: r.GasWanted,
+		GasUsed:   r.GasUsed,
+	}
+	return res.Marshal()
+}
+
+// ResultLeaves returns the leaves of the LastResultsHash Merkle tree.
+func ResultLeaves(results []*abci.ResponseDeliverTx) ([][]byte, error) {
+	leaves := make([][]byte, len(results))
+	for i, res := range results {
+		if res == nil {
+			return nil, fmt.Errorf("%w: result #%d is nil", ErrInvalidResult, i)
+		}
+		bz, err := NewDeliverTxResult(res).Bytes()
+		if err != nil {
+			return nil, fmt.Errorf("%w: encode result #%d: %v", ErrInvalidResult, i, err)
+		}
+		leaves[i] = bz
+	}
+	return leaves, nil
+}
+
+// ResultsHash returns the LastResultsHash committed to by the header of the
+// block after the one that produced results.
+func ResultsHash(results []*abci.ResponseDeliverTx) ([]byte, error) {
+	leaves, err := ResultLeaves(results)
+	if err != nil {
+		return nil, err
+	}
+	return merkle.HashFromByteSlices(leaves), nil
+}
+
+// ResultInclusionProof returns the Merkle proof of results[index] against
+// ResultsHash(results).
+func ResultInclusionProof(results []*abci.ResponseDeliverTx, index int) (root []byte, proof *merkle.Proof, err error) {
+	if index < 0 || index >= len(results) {
+		return nil, nil, fmt.Errorf("%w: result index %d not in [0, %d)", ErrIndexOutOfRange, index, len(results))
+	}
+
+	leaves, err := ResultLeaves(results)
+	if err != nil {
+		return nil, nil, err
+	}
+	root, proofs := merkle.ProofsFromByteSlices(leaves)
+	return root, proofs[index], nil
+}
+
+// BlockResultProof proves the result of a tx: the result against the
+// LastResultsHash of the next header, then the LastResultsHash against the
+// next block hash.
+type BlockResultProof struct {
+	Result               DeliverTxResult  `json:"result"`
+	ResultProof          *merkle.Proof    `json:"result_proof"`
+	LastResultsHash      tmbytes.HexBytes `json:"last_results_hash"`
+	LastResultsHashProof *merkle.Proof    `json:"last_results_hash_proof"`
+}
+
+// ProveResultInBlock returns the proof of results[index], the results of the
+// block before nextHeader, against nextHeader.
+func ProveResultInBlock(nextHeader *types.Header, results []*abci.ResponseDeliverTx, index int) (*BlockResultProof, error) {
+	if nextHeader == nil {
+		return nil, fmt.Errorf("%w: nil header", header.ErrInvalidHeader)
+	}
+
+	root, proof, err := ResultInclusionProof(results, index)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(root, nextHeader.LastResultsHash) {
+		return nil, fmt.Errorf("%w: results hash %X, header last results hash %X", ErrRootMismatch, root, nextHeader.LastResultsHash)
+	}
+
+	_, hashProof, _, err := header.HeaderFieldProof(nextHeader, header.FieldLastResultsHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockResultProof{
+		Result:               NewDeliverTxResult(results[index]),
+		ResultProof:          proof,
+		LastResultsHash:      nextHeader.LastResultsHash,
+		LastResultsHashProof: hashProof,
+	}, nil
+}
+
+// VerifyResultInBlock checks that proof proves proof.Result against the block
+// hash of the header that follows the tx's block.
+func VerifyResultInBlock(nextBlockHash []byte, proof *BlockResultProof) error {
+	if proof == nil || proof.ResultProof == nil {
+		return fmt.Errorf("%w: nil result proof", ErrInvalidProof)
+	}
+
+	leaf, err := proof.Result.Bytes()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidResult, err)
+	}
+	if err := proof.ResultProof.Verify(proof.LastResultsHash, leaf); err != nil {
+		return fmt.Errorf("%w: verify result against last results hash: %v", ErrInvalidProof, err)
+	}
+	if err := header.VerifyHeaderField(nextBlockHash, header.FieldLastResultsHash, proof.LastResultsHash, proof.LastResultsHashProof); err != nil {
+		return fmt.Errorf("verify last results hash against block hash: %w", err)
+	}
+	return nil
+}
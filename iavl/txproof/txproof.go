@@ -0,0 +1,89 @@
+package txproof
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/tendermint/tendermint/crypto/merkle"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/types"
+
+	"server/headerTest/header"
+)
+
+// Leaves returns the leaves of the DataHash Merkle tree: the hash of each tx.
+func Leaves(txs types.Txs) [][]byte {
+	txBzs := make([][]byte, len(txs))
+	for i := 0; i < len(txs); i++ {
+		txBzs[i] = txs[i].Hash()
+	}
+	return txBzs
+}
+
+// TxInclusionProof returns the Merkle proof of txs[index] against txs.Hash(),
+// which is the DataHash of the block containing txs.
+func TxInclusionProof(txs types.Txs, index int) (types.TxProof, error) {
+	if index < 0 || index >= len(txs) {
+		return types.TxProof{}, fmt.Errorf("%w: tx index %d not in [0, %d)", ErrIndexOutOfRange, index, len(txs))
+	}
+
+	root, proofs := merkle.ProofsFromByteSlices(Leaves(txs))
+	return types.TxProof{
+		RootHash: root,
+		Data:     txs[index],
+		Proof:    *proofs[index],
+	}, nil
+}
+
+// BlockTxProof proves that a tx is included in a block: the tx hash against
+// the header's DataHash, then the DataHash against the block hash.
+type BlockTxProof struct {
+	Tx            types.Tx         `json:"tx"`
+	TxProof       *merkle.Proof    `json:"tx_proof"`
+	DataHash      tmbytes.HexBytes `json:"data_hash"`
+	DataHashProof *merkle.Proof    `json:"data_hash_proof"`
+}
+
+// ProveTxInBlock returns the proof that txs[index] is included in the block
+// with header h. txs must be all the txs of that block, in order.
+func ProveTxInBlock(h *types.Header, txs types.Txs, index int) (*BlockTxProof, error) {
+	if h == nil {
+		return nil, fmt.Errorf("%w: nil header", header.ErrInvalidHeader)
+	}
+
+	txProof, err := TxInclusionProof(txs, index)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(txProof.RootHash, h.DataHash) {
+		return nil, fmt.Errorf("%w: txs hash %X, header data hash %X", ErrRootMismatch, txProof.RootHash, h.DataHash)
+	}
+
+	_, dataHashProof, _, err := header.HeaderFieldProof(h, header.FieldDataHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockTxProof{
+		Tx:            txProof.Data,
+		TxProof:       &txProof.Proof,
+		DataHash:      h.DataHash,
+		DataHashProof: dataHashProof,
+	}, nil
+}
+
+// VerifyTxInBlock checks that proof proves proof.Tx is included in the block
+// with the given hash.
+func VerifyTxInBlock(blockHash []byte, proof *BlockTxProof) error {
+	if proof == nil || proof.TxProof == nil {
+		return fmt.Errorf("%w: nil tx proof", ErrInvalidProof)
+	}
+
+	if err := proof.TxProof.Verify(proof.DataHash, proof.Tx.Hash()); err != nil {
+		return fmt.Errorf("%w: verify tx against data hash: %v", ErrInvalidProof, err)
+	}
+	if err := header.VerifyHeaderField(blockHash, header.FieldDataHash, proof.DataHash, proof.DataHashProof); err != nil {
+		return fmt.Errorf("verify data hash against block hash: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package txproof
+
+import (
+	"errors"
+	"testing"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/types"
+
+	"server/headerTest/header"
+)
+
+// testHeader returns a header committing to txs and, as the next header, to
+// results.
+func testHeader(t *testing.T, txs types.Txs, results []*abci.ResponseDeliverTx) *types.Header {
+	t.Helper()
+	resultsHash, err := ResultsHash(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &types.Header{
+		ChainID:         "test-chain",
+		Height:          10,
+		DataHash:        txs.Hash(),
+		ValidatorsHash:  tmhash.Sum([]byte("vals")),
+		LastResultsHash: resultsHash,
+	}
+}
+
+func TestTxInBlock(t *testing.T) {
+	txs := types.Txs{types.Tx("a"), types.Tx("b"), types.Tx("c")}
+	h := testHeader(t, txs, nil)
+
+	for i := range txs {
+		proof, err := ProveTxInBlock(h, txs, i)
+		if err != nil {
+			t.Fatalf("prove tx %d: %v", i, err)
+		}
+		if err := VerifyTxInBlock(h.Hash(), proof); err != nil {
+			t.Errorf("verify tx %d: %v", i, err)
+		}
+	}
+
+	proof, err := ProveTxInBlock(h, txs, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherTx := *proof
+	otherTx.Tx = types.Tx("x")
+	otherDataHash := *proof
+	otherDataHash.DataHash = tmhash.Sum([]byte("other"))
+
+	tests := []struct {
+		name  string
+		proof *BlockTxProof
+		want  error
+	}{
+		{"nil proof", nil, ErrInvalidProof},
+		{"other tx", &otherTx, ErrInvalidProof},
+		{"other data hash", &otherDataHash, ErrInvalidProof},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := VerifyTxInBlock(h.Hash(), tc.proof); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+	if err := VerifyTxInBlock(tmhash.Sum([]byte("other")), proof); !errors.Is(err, header.ErrInvalidProof) {
+		t.Errorf("other block: got %v, want %v", err, header.ErrInvalidProof)
+	}
+}
+
+func TestProveTxInBlockErrors(t *testing.T) {
+	txs := types.Txs{types.Tx("a"), types.Tx("b")}
+	h := testHeader(t, txs, nil)
+
+	tests := []struct {
+		name  string
+		h     *types.Header
+		txs   types.Txs
+		index int
+		want  error
+	}{
+		{"nil header", nil, txs, 0, header.ErrInvalidHeader},
+		{"index out of range", h, txs, 2, ErrIndexOutOfRange},
+		{"negative index", h, txs, -1, ErrIndexOutOfRange},
+		{"txs of another block", h, types.Txs{types.Tx("a")}, 0, ErrRootMismatch},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ProveTxInBlock(tc.h, tc.txs, tc.index); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestResultInBlock(t *testing.T) {
+	results := []*abci.ResponseDeliverTx{
+		{Code: 0, Data: []byte("ok"), GasWanted: 100, GasUsed: 90},
+		// events and logs are not committed to
+		{Code: 5, GasWanted: 100, GasUsed: 100, Log: "out of gas", Events: []abci.Event{{Type: "tx"}}},
+	}
+	next := testHeader(t, nil, results)
+
+	for i := range results {
+		proof, err := ProveResultInBlock(next, results, i)
+		if err != nil {
+			t.Fatalf("prove result %d: %v", i, err)
+		}
+		if err := VerifyResultInBlock(next.Hash(), proof); err != nil {
+			t.Errorf("verify result %d: %v", i, err)
+		}
+	}
+
+	proof, err := ProveResultInBlock(next, results, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof.Result.Code = 0
+	if err := VerifyResultInBlock(next.Hash(), proof); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("other result: got %v, want %v", err, ErrInvalidProof)
+	}
+
+	if _, err := ProveResultInBlock(nil, results, 0); !errors.Is(err, header.ErrInvalidHeader) {
+		t.Errorf("nil header: got %v, want %v", err, header.ErrInvalidHeader)
+	}
+	if _, err := ProveResultInBlock(next, results[:1], 0); !errors.Is(err, ErrRootMismatch) {
+		t.Errorf("results of another block: got %v, want %v", err, ErrRootMismatch)
+	}
+	if _, err := ResultsHash([]*abci.ResponseDeliverTx{nil}); !errors.Is(err, ErrInvalidResult) {
+		t.Errorf("nil result: got %v, want %v", err, ErrInvalidResult)
+	}
+}
@@ -0,0 +1,125 @@
+package light
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// ErrInvalidTarget is returned when there is nothing to verify towards: a
+// target below the trusted height, or no trace to compare with witnesses.
+var ErrInvalidTarget = errors.New("invalid verification target")
+
+// Client verifies headers fetched from a Provider.
+type Client struct {
+	provider Provider
+	opts     Options
+	now      func() time.Time
+}
+
+// NewClient returns a client that fetches light blocks from provider. now may
+// be nil to use the system clock.
+func NewClient(provider Provider, opts Options, now func() time.Time) (*Client, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("%w: nil provider", ErrInvalidOptions)
+	}
+	if err := opts.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &Client{provider: provider, opts: opts, now: now}, nil
+}
+
+// Result is the outcome of VerifyToHeight. Trace lists the headers verified
+// on the way to the target, which is the last of them, and Blocks the
+// corresponding light blocks.
+type Result struct {
+	Verified *types.LightBlock   `json:"verified"`
+	Trace    []Step              `json:"trace"`
+	Blocks   []*types.LightBlock `json:"-"`
+}
+
+// VerifyToHeight verifies the light block at height, or the latest one if
+// height is 0, starting from trusted. It first tries to skip straight to the
+// target and, each time too little of the trusted validator set signed the
+// header tried, bisects to the midpoint between it and the last verified
+// header. On failure the returned result holds the headers verified so far.
+func (c *Client) VerifyToHeight(ctx context.Context, trusted *types.LightBlock, height int64) (*Result, error) {
+	if err := ValidateLightBlock(trusted); err != nil {
+		return nil, fmt.Errorf("trusted block: %w", err)
+	}
+	res := &Result{Verified: trusted}
+
+	target, err := c.provider.LightBlock(ctx, height)
+	if err != nil {
+		return res, err
+	}
+	if height != 0 && target.Height != height {
+		return res, fmt.Errorf("%w: provider returned height %d for %d", ErrInvalidHeader, target.Height, height)
+	}
+	return c.verifySkipping(ctx, trusted, target)
+}
+
+// verifySkipping verifies target from trusted, fetching the bisection pivots
+// from the provider.
+func (c *Client) verifySkipping(ctx context.Context, trusted, target *types.LightBlock) (*Result, error) {
+	res := &Result{Verified: trusted}
+
+	switch {
+	case target.Height < trusted.Height:
+		return res, fmt.Errorf("%w: target height %d is below trusted height %d", ErrInvalidTarget, target.Height, trusted.Height)
+	case target.Height == trusted.Height:
+		if !bytes.Equal(target.Hash(), trusted.Hash()) {
+			return res, fmt.Errorf("%w: header %d hash %X does not match trusted hash %X",
+				ErrInvalidHeader, target.Height, target.Hash(), trusted.Hash())
+		}
+		return res, nil
+	}
+
+	// pending holds the headers still to verify, the target first and each
+	// bisection pivot after it.
+	pending := []*types.LightBlock{target}
+	depth := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+
+		untrusted := pending[depth]
+		step, err := Verify(res.Verified, untrusted, c.opts, c.now())
+		switch {
+		case err == nil:
+			res.Verified = untrusted
+			res.Trace = append(res.Trace, *step)
+			res.Blocks = append(res.Blocks, untrusted)
+			if depth == 0 {
+				return res, nil
+			}
+			pending = pending[:depth]
+			depth = 0
+
+		case errors.Is(err, ErrNewValSetCantBeTrusted):
+			if depth == len(pending)-1 {
+				pivot := res.Verified.Height + (untrusted.Height-res.Verified.Height)/2
+				lb, err := c.provider.LightBlock(ctx, pivot)
+				if err != nil {
+					return res, fmt.Errorf("bisect to height %d: %w", pivot, err)
+				}
+				if lb.Height != pivot {
+					return res, fmt.Errorf("%w: provider returned height %d for %d", ErrInvalidHeader, lb.Height, pivot)
+				}
+				pending = append(pending, lb)
+			}
+			depth++
+
+		default:
+			return res, fmt.Errorf("verify header %d from %d: %w", untrusted.Height, res.Verified.Height, err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package light
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestVerifyAdjacent(t *testing.T) {
+	keys := genKeys(4)
+	blocks := newChainGen(t, keys).blocks(nil, staticSets(genValSet(t, keys), 5))
+
+	for i := 1; i < len(blocks); i++ {
+		step, err := Verify(blocks[i-1], blocks[i], DefaultOptions(), testNow())
+		if err != nil {
+			t.Fatalf("verify %d from %d: %v", blocks[i].Height, blocks[i-1].Height, err)
+		}
+		if step.Mode != ModeAdjacent {
+			t.Errorf("height %d: mode %q, want %q", step.Height, step.Mode, ModeAdjacent)
+		}
+		if step.SignedVotingPower != step.TotalVotingPower {
+			t.Errorf("height %d: signed %d of %d", step.Height, step.SignedVotingPower, step.TotalVotingPower)
+		}
+	}
+}
+
+func TestVerifyAdjacentNotLinked(t *testing.T) {
+	keys := genKeys(4)
+	g := newChainGen(t, keys)
+	sets := staticSets(genValSet(t, keys), 3)
+	blocks := g.blocks(nil, sets)
+
+	// a validly signed block 3 that does not follow block 2
+	g.mutate = func(h *types.Header) { h.LastBlockID.Hash = tmhash.Sum([]byte("other")) }
+	other := g.blocks(blocks[1], sets)[0]
+
+	if _, err := Verify(blocks[1], other, DefaultOptions(), testNow()); !errors.Is(err, ErrInvalidHeader) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidHeader)
+	}
+}
+
+func TestVerifySkipping(t *testing.T) {
+	keys := genKeys(4)
+	blocks := newChainGen(t, keys).blocks(nil, staticSets(genValSet(t, keys), 10))
+
+	res, err := newTestClient(t, blocks).VerifyToHeight(context.Background(), blocks[0], 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Trace) != 1 {
+		t.Fatalf("trace has %d steps, want 1", len(res.Trace))
+	}
+	if step := res.Trace[0]; step.Height != 10 || step.Mode != ModeSkipping {
+		t.Errorf("step to height %d in mode %q, want 10 in mode %q", step.Height, step.Mode, ModeSkipping)
+	}
+	if res.Verified != blocks[9] {
+		t.Errorf("verified height %d, want 10", res.Verified.Height)
+	}
+}
+
+func TestVerifyExpired(t *testing.T) {
+	keys := genKeys(4)
+	blocks := newChainGen(t, keys).blocks(nil, staticSets(genValSet(t, keys), 2))
+
+	opts := DefaultOptions()
+	now := blocks[0].Time.Add(opts.TrustingPeriod)
+	if _, err := Verify(blocks[0], blocks[1], opts, now); !errors.Is(err, ErrOldHeaderExpired) {
+		t.Fatalf("got %v, want %v", err, ErrOldHeaderExpired)
+	}
+}
+
+// rotatingSets returns the validator sets of n heights where height h is
+// validated by keys h-1 to h+2, so sets three heights apart share no
+// validator.
+func rotatingSets(t *testing.T, keys []types.PrivValidator, n int) []*types.ValidatorSet {
+	t.Helper()
+	sets := make([]*types.ValidatorSet, n)
+	for i := range sets {
+		sets[i] = genValSet(t, keys[i:i+4])
+	}
+	return sets
+}
+
+func TestVerifyRotation(t *testing.T) {
+	keys := genKeys(13)
+	blocks := newChainGen(t, keys).blocks(nil, rotatingSets(t, keys, 10))
+
+	if _, err := Verify(blocks[0], blocks[9], DefaultOptions(), testNow()); !errors.Is(err, ErrNewValSetCantBeTrusted) {
+		t.Fatalf("skip over a rotated validator set: got %v, want %v", err, ErrNewValSetCantBeTrusted)
+	}
+
+	res, err := newTestClient(t, blocks).VerifyToHeight(context.Background(), blocks[0], 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Verified != blocks[9] {
+		t.Fatalf("verified height %d, want 10", res.Verified.Height)
+	}
+	if len(res.Trace) < 2 {
+		t.Fatalf("trace has %d steps, want bisection", len(res.Trace))
+	}
+	// the trace climbs from the trusted height, one block per step
+	prev := blocks[0]
+	for i, step := range res.Trace {
+		if step.Height <= prev.Height {
+			t.Fatalf("step %d to height %d after height %d", i, step.Height, prev.Height)
+		}
+		if res.Blocks[i].Height != step.Height {
+			t.Fatalf("step %d at height %d, block at %d", i, step.Height, res.Blocks[i].Height)
+		}
+		prev = res.Blocks[i]
+	}
+}
+
+func TestVerifyRotationMissingPivot(t *testing.T) {
+	keys := genKeys(13)
+	blocks := newChainGen(t, keys).blocks(nil, rotatingSets(t, keys, 10))
+
+	// without the pivots the client cannot bisect
+	c := newTestClient(t, []*types.LightBlock{blocks[0], blocks[9]})
+	res, err := c.VerifyToHeight(context.Background(), blocks[0], 10)
+	if !errors.Is(err, ErrLightBlockNotFound) {
+		t.Fatalf("got %v, want %v", err, ErrLightBlockNotFound)
+	}
+	if res.Verified != blocks[0] {
+		t.Errorf("verified height %d, want the trusted 1", res.Verified.Height)
+	}
+}
@@ -0,0 +1,239 @@
+package light
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/types"
+)
+
+// errNoDivergence is returned by examine when the source agrees with the
+// whole trace.
+var errNoDivergence = errors.New("sources did not diverge")
+
+// AttackType classifies a light client attack.
+type AttackType string
+
+const (
+	// AttackLunatic is a conflicting header whose application state or
+	// validator sets could not result from a valid state transition.
+	AttackLunatic AttackType = "lunatic"
+	// AttackEquivocation is a conflicting header signed in the same round
+	// as the trusted one, so its signers double-signed.
+	AttackEquivocation AttackType = "equivocation"
+	// AttackAmnesia is a conflicting header signed in a different round.
+	AttackAmnesia AttackType = "amnesia"
+)
+
+// Evidence is a LightClientAttackEvidence along with its classification and
+// protobuf encoding, ready to be submitted to a full node.
+type Evidence struct {
+	Type                AttackType         `json:"type"`
+	ConflictingHeight   int64              `json:"conflicting_height"`
+	ConflictingHash     tmbytes.HexBytes   `json:"conflicting_hash"`
+	CommonHeight        int64              `json:"common_height"`
+	TotalVotingPower    int64              `json:"total_voting_power"`
+	Timestamp           time.Time          `json:"timestamp"`
+	ByzantineValidators []tmbytes.HexBytes `json:"byzantine_validators"`
+	Proto               []byte             `json:"proto,omitempty"`
+
+	Evidence *types.LightClientAttackEvidence `json:"-"`
+}
+
+// NewEvidence builds the evidence that conflicting was committed although
+// trusted, at the same height, was verified from common, the last block both
+// sources agree on.
+func NewEvidence(conflicting, trusted, common *types.LightBlock) (*Evidence, error) {
+	ev := &types.LightClientAttackEvidence{ConflictingBlock: conflicting}
+
+	typ := AttackLunatic
+	if ev.ConflictingHeaderIsInvalid(trusted.Header) {
+		// a lunatic attack is punished from the common height on, the
+		// others at the conflicting height
+		ev.CommonHeight = common.Height
+		ev.Timestamp = common.Time
+		ev.TotalVotingPower = common.ValidatorSet.TotalVotingPower()
+	} else {
+		typ = AttackAmnesia
+		if trusted.Commit.Round == conflicting.Commit.Round {
+			typ = AttackEquivocation
+		}
+		ev.CommonHeight = trusted.Height
+		ev.Timestamp = trusted.Time
+		ev.TotalVotingPower = trusted.ValidatorSet.TotalVotingPower()
+	}
+	ev.ByzantineValidators = ev.GetByzantineValidators(common.ValidatorSet, trusted.SignedHeader)
+
+	res := &Evidence{
+		Type:              typ,
+		ConflictingHeight: conflicting.Height,
+		ConflictingHash:   conflicting.Hash(),
+		CommonHeight:      ev.CommonHeight,
+		TotalVotingPower:  ev.TotalVotingPower,
+		Timestamp:         ev.Timestamp,
+		Evidence:          ev,
+	}
+	for _, val := range ev.ByzantineValidators {
+		res.ByzantineValidators = append(res.ByzantineValidators, val.Address)
+	}
+
+	pb, err := ev.ToProto()
+	if err != nil {
+		return nil, fmt.Errorf("encode evidence: %w", err)
+	}
+	if res.Proto, err = pb.Marshal(); err != nil {
+		return nil, fmt.Errorf("encode evidence: %w", err)
+	}
+	return res, nil
+}
+
+// Conflict is a witness that served a verifiable header conflicting with the
+// primary's. AgainstWitness is nil, with Error telling why, if the primary
+// could not be examined from the witness's trace.
+type Conflict struct {
+	Witness          int       `json:"witness"`
+	DivergenceHeight int64     `json:"divergence_height"`
+	AgainstPrimary   *Evidence `json:"against_primary"`
+	AgainstWitness   *Evidence `json:"against_witness,omitempty"`
+	Error            string    `json:"error,omitempty"`
+}
+
+// FaultyWitness is a witness that failed to serve a block or served one that
+// does not verify.
+type FaultyWitness struct {
+	Witness int    `json:"witness"`
+	Error   string `json:"error"`
+}
+
+// Detection is the outcome of DetectForks.
+type Detection struct {
+	Height          int64            `json:"height"`
+	Hash            tmbytes.HexBytes `json:"hash"`
+	Conflicts       []Conflict       `json:"conflicts,omitempty"`
+	FaultyWitnesses []FaultyWitness  `json:"faulty_witnesses,omitempty"`
+}
+
+// Attacked reports whether any witness proved a conflicting header.
+func (d *Detection) Attacked() bool {
+	return len(d.Conflicts) > 0
+}
+
+// DetectForks compares the block verified in res, from trusted, with the
+// block each witness has at the same height. When a witness differs, the
+// primary's trace is walked to find the first height where the witness
+// diverges, verifying the witness's blocks from the last common one by
+// bisection. If the witness's conflicting header verifies, both sources
+// produced a valid header at that height and evidence against each is built.
+func (c *Client) DetectForks(ctx context.Context, trusted *types.LightBlock, res *Result, witnesses []Provider) (*Detection, error) {
+	if res == nil || len(res.Blocks) == 0 {
+		return nil, fmt.Errorf("%w: no verified trace to compare", ErrInvalidTarget)
+	}
+	primaryTrace := append([]*types.LightBlock{trusted}, res.Blocks...)
+	target := primaryTrace[len(primaryTrace)-1]
+
+	d := &Detection{Height: target.Height, Hash: target.Hash()}
+	for i, witness := range witnesses {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		wb, err := witness.LightBlock(ctx, target.Height)
+		if err == nil && wb.Height != target.Height {
+			err = fmt.Errorf("%w: witness returned height %d for %d", ErrInvalidHeader, wb.Height, target.Height)
+		}
+		if err != nil {
+			d.FaultyWitnesses = append(d.FaultyWitnesses, FaultyWitness{Witness: i, Error: err.Error()})
+			continue
+		}
+		if bytes.Equal(wb.Hash(), target.Hash()) {
+			continue
+		}
+
+		conflict, err := c.handleConflict(ctx, primaryTrace, wb, witness)
+		if err != nil {
+			d.FaultyWitnesses = append(d.FaultyWitnesses, FaultyWitness{Witness: i, Error: err.Error()})
+			continue
+		}
+		conflict.Witness = i
+		d.Conflicts = append(d.Conflicts, *conflict)
+	}
+	return d, nil
+}
+
+// handleConflict builds the evidence for a witness block conflicting with
+// the end of primaryTrace. It fails if the witness's block does not verify,
+// in which case the witness rather than the primary is faulty.
+func (c *Client) handleConflict(ctx context.Context, primaryTrace []*types.LightBlock,
+	witnessBlock *types.LightBlock, witness Provider) (*Conflict, error) {
+	// hold the witness as the source of truth first
+	witnessTrace, primaryBlock, err := c.examine(ctx, primaryTrace, witnessBlock, witness)
+	if err != nil {
+		return nil, fmt.Errorf("witness header does not verify: %w", err)
+	}
+	common, trusted := witnessTrace[0], witnessTrace[len(witnessTrace)-1]
+	againstPrimary, err := NewEvidence(primaryBlock, trusted, common)
+	if err != nil {
+		return nil, err
+	}
+	conflict := &Conflict{DivergenceHeight: primaryBlock.Height, AgainstPrimary: againstPrimary}
+
+	// then hold the primary as the source of truth; if that fails there is
+	// still the evidence against the primary
+	primaryTrace, conflicting, err := c.examine(ctx, witnessTrace, primaryBlock, c.provider)
+	if err != nil {
+		conflict.Error = fmt.Sprintf("examine primary: %v", err)
+		return conflict, nil
+	}
+	common, trusted = primaryTrace[0], primaryTrace[len(primaryTrace)-1]
+	if conflict.AgainstWitness, err = NewEvidence(conflicting, trusted, common); err != nil {
+		conflict.Error = fmt.Sprintf("evidence against witness: %v", err)
+	}
+	return conflict, nil
+}
+
+// examine walks trace, verifying the block source has at each of its heights
+// from the last block both agree on, until they diverge. It returns source's
+// verified trace from that common block and the block of trace where source
+// diverged. The block of source at the height of target is target itself.
+func (c *Client) examine(ctx context.Context, trace []*types.LightBlock, target *types.LightBlock,
+	source Provider) ([]*types.LightBlock, *types.LightBlock, error) {
+	sc := &Client{provider: source, opts: c.opts, now: c.now}
+
+	var common *types.LightBlock
+	for idx, traceBlock := range trace {
+		if traceBlock.Height > target.Height {
+			break
+		}
+
+		sourceBlock := target
+		if traceBlock.Height != target.Height {
+			var err error
+			if sourceBlock, err = source.LightBlock(ctx, traceBlock.Height); err != nil {
+				return nil, nil, fmt.Errorf("examine height %d: %w", traceBlock.Height, err)
+			}
+		}
+
+		if idx == 0 {
+			if !bytes.Equal(sourceBlock.Hash(), traceBlock.Hash()) {
+				return nil, nil, fmt.Errorf("%w: trusted block %X differs from the source's %X",
+					ErrInvalidHeader, traceBlock.Hash(), sourceBlock.Hash())
+			}
+			common = sourceBlock
+			continue
+		}
+
+		res, err := sc.verifySkipping(ctx, common, sourceBlock)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !bytes.Equal(sourceBlock.Hash(), traceBlock.Hash()) {
+			return append([]*types.LightBlock{common}, res.Blocks...), traceBlock, nil
+		}
+		common = sourceBlock
+	}
+	return nil, nil, errNoDivergence
+}
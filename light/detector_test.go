@@ -0,0 +1,137 @@
+package light
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/types"
+)
+
+// forkTest is a primary chain of 5 heights and a witness that forks from it
+// at height 3.
+type forkTest struct {
+	primary, witness []*types.LightBlock
+}
+
+// newForkTest generates the fork, signing the witness's blocks with g after
+// the common ones.
+func newForkTest(t *testing.T, keys []types.PrivValidator, g *chainGen) forkTest {
+	t.Helper()
+	sets := staticSets(genValSet(t, keys), 5)
+	primary := newChainGen(t, keys).blocks(nil, sets)
+	witness := append(append([]*types.LightBlock(nil), primary[:2]...), g.blocks(primary[1], sets)...)
+	return forkTest{primary: primary, witness: witness}
+}
+
+// detect verifies the primary to its last height and compares it with
+// witnesses.
+func (f forkTest) detect(t *testing.T, witnesses ...Provider) *Detection {
+	t.Helper()
+	ctx := context.Background()
+	c := newTestClient(t, f.primary)
+	res, err := c.VerifyToHeight(ctx, f.primary[0], f.primary[len(f.primary)-1].Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := c.DetectForks(ctx, f.primary[0], res, witnesses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// checkConflict checks that d holds one conflict with evidence of typ
+// against both sources.
+func checkConflict(t *testing.T, d *Detection, typ AttackType) {
+	t.Helper()
+	if !d.Attacked() || len(d.Conflicts) != 1 {
+		t.Fatalf("got %d conflicts, want 1 (faulty witnesses: %+v)", len(d.Conflicts), d.FaultyWitnesses)
+	}
+	c := d.Conflicts[0]
+	if c.Error != "" {
+		t.Fatalf("conflict error: %s", c.Error)
+	}
+	for name, ev := range map[string]*Evidence{"primary": c.AgainstPrimary, "witness": c.AgainstWitness} {
+		if ev == nil {
+			t.Fatalf("no evidence against the %s", name)
+		}
+		if ev.Type != typ {
+			t.Errorf("evidence against the %s is %q, want %q", name, ev.Type, typ)
+		}
+		if len(ev.Proto) == 0 {
+			t.Errorf("evidence against the %s is not encoded", name)
+		}
+	}
+}
+
+func TestDetectNoFork(t *testing.T) {
+	keys := genKeys(4)
+	f := newForkTest(t, keys, newChainGen(t, keys))
+
+	d := f.detect(t, NewFixtures(f.witness...))
+	if d.Attacked() || len(d.FaultyWitnesses) != 0 {
+		t.Fatalf("conflicts %+v, faulty witnesses %+v", d.Conflicts, d.FaultyWitnesses)
+	}
+}
+
+func TestDetectLunatic(t *testing.T) {
+	keys := genKeys(4)
+	g := newChainGen(t, keys)
+	g.mutate = func(h *types.Header) { h.AppHash = tmhash.Sum([]byte("lunatic")) }
+	f := newForkTest(t, keys, g)
+
+	d := f.detect(t, NewFixtures(f.witness...))
+	checkConflict(t, d, AttackLunatic)
+	ev := d.Conflicts[0].AgainstPrimary
+	if ev.CommonHeight != 1 {
+		t.Errorf("common height %d, want 1", ev.CommonHeight)
+	}
+	if len(ev.ByzantineValidators) != len(keys) {
+		t.Errorf("%d byzantine validators, want all %d", len(ev.ByzantineValidators), len(keys))
+	}
+}
+
+func TestDetectEquivocation(t *testing.T) {
+	keys := genKeys(4)
+	g := newChainGen(t, keys)
+	g.mutate = func(h *types.Header) { h.DataHash = tmhash.Sum([]byte("other txs")) }
+	f := newForkTest(t, keys, g)
+
+	d := f.detect(t, NewFixtures(f.witness...))
+	checkConflict(t, d, AttackEquivocation)
+	if got := len(d.Conflicts[0].AgainstPrimary.ByzantineValidators); got != len(keys) {
+		t.Errorf("%d byzantine validators, want all %d", got, len(keys))
+	}
+}
+
+func TestDetectAmnesia(t *testing.T) {
+	keys := genKeys(4)
+	g := newChainGen(t, keys)
+	g.round = 2
+	g.mutate = func(h *types.Header) { h.DataHash = tmhash.Sum([]byte("other txs")) }
+	f := newForkTest(t, keys, g)
+
+	// amnesia does not prove who misbehaved
+	d := f.detect(t, NewFixtures(f.witness...))
+	checkConflict(t, d, AttackAmnesia)
+	if got := len(d.Conflicts[0].AgainstPrimary.ByzantineValidators); got != 0 {
+		t.Errorf("%d byzantine validators, want none", got)
+	}
+}
+
+func TestDetectFaultyWitness(t *testing.T) {
+	keys := genKeys(4)
+	// the witness's fork is signed by validators unknown to the chain
+	g := newChainGen(t, genKeys(4))
+	g.mutate = func(h *types.Header) { h.AppHash = tmhash.Sum([]byte("lunatic")) }
+	f := newForkTest(t, keys, g)
+
+	d := f.detect(t, NewFixtures(f.witness...), NewFixtures(f.primary[:2]...))
+	if d.Attacked() {
+		t.Fatalf("conflicts %+v from a faulty witness", d.Conflicts)
+	}
+	if len(d.FaultyWitnesses) != 2 {
+		t.Fatalf("got %d faulty witnesses, want 2", len(d.FaultyWitnesses))
+	}
+}
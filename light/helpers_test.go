@@ -0,0 +1,169 @@
+package light
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
+)
+
+const testChainID = "test-chain"
+
+// genesisTime is the time of height 0; block h is h minutes later.
+var genesisTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testNow is a time at which every generated block is trusted.
+func testNow() time.Time {
+	return genesisTime.Add(24 * time.Hour)
+}
+
+// genKeys returns n mock private validators.
+func genKeys(n int) []types.PrivValidator {
+	keys := make([]types.PrivValidator, n)
+	for i := range keys {
+		keys[i] = types.NewMockPV()
+	}
+	return keys
+}
+
+// genValSet returns the validator set of keys, each with voting power 10.
+func genValSet(t *testing.T, keys []types.PrivValidator) *types.ValidatorSet {
+	t.Helper()
+	vals := make([]*types.Validator, len(keys))
+	for i, key := range keys {
+		pubKey, err := key.GetPubKey(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		vals[i] = types.NewValidator(pubKey, 10)
+	}
+	return types.NewValidatorSet(vals)
+}
+
+// chainGen generates the light blocks of a chain signed by keys.
+type chainGen struct {
+	t    *testing.T
+	keys map[string]types.PrivValidator
+	// round is the round the commits are signed in.
+	round int32
+	// mutate, if set, alters each header before it is signed.
+	mutate func(*types.Header)
+}
+
+func newChainGen(t *testing.T, keys []types.PrivValidator) *chainGen {
+	t.Helper()
+	g := &chainGen{t: t, keys: make(map[string]types.PrivValidator, len(keys)), round: 1}
+	for _, key := range keys {
+		pubKey, err := key.GetPubKey(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		g.keys[string(pubKey.Address())] = key
+	}
+	return g
+}
+
+// blocks generates the light blocks following prev, or from height 1 if prev
+// is nil, up to height len(sets). sets[h-1] is the validator set of height h,
+// and the last one stays the next validator set after it.
+func (g *chainGen) blocks(prev *types.LightBlock, sets []*types.ValidatorSet) []*types.LightBlock {
+	g.t.Helper()
+	var (
+		blocks  []*types.LightBlock
+		lastBID types.BlockID
+		height  = int64(1)
+	)
+	if prev != nil {
+		lastBID = prev.Commit.BlockID
+		height = prev.Height + 1
+	}
+	for ; height <= int64(len(sets)); height++ {
+		vals, next := sets[height-1], sets[height-1]
+		if height < int64(len(sets)) {
+			next = sets[height]
+		}
+		h := &types.Header{
+			Version:            version.Consensus{Block: version.BlockProtocol},
+			ChainID:            testChainID,
+			Height:             height,
+			Time:               genesisTime.Add(time.Duration(height) * time.Minute),
+			LastBlockID:        lastBID,
+			ValidatorsHash:     vals.Hash(),
+			NextValidatorsHash: next.Hash(),
+			ConsensusHash:      tmhash.Sum([]byte("consensus")),
+			AppHash:            tmhash.Sum([]byte("app")),
+			LastResultsHash:    tmhash.Sum([]byte("results")),
+			ProposerAddress:    vals.Validators[0].Address,
+		}
+		if g.mutate != nil {
+			g.mutate(h)
+		}
+		lb := &types.LightBlock{
+			SignedHeader: &types.SignedHeader{Header: h, Commit: g.sign(h, vals)},
+			ValidatorSet: vals,
+		}
+		blocks = append(blocks, lb)
+		lastBID = lb.Commit.BlockID
+	}
+	return blocks
+}
+
+// sign returns the commit of h precommitted by every validator of vals.
+func (g *chainGen) sign(h *types.Header, vals *types.ValidatorSet) *types.Commit {
+	g.t.Helper()
+	blockID := types.BlockID{
+		Hash: h.Hash(),
+		PartSetHeader: types.PartSetHeader{
+			Total: 1,
+			Hash:  tmhash.Sum([]byte(fmt.Sprintf("parts %d", h.Height))),
+		},
+	}
+	sigs := make([]types.CommitSig, len(vals.Validators))
+	for i, val := range vals.Validators {
+		key, ok := g.keys[string(val.Address)]
+		if !ok {
+			sigs[i] = types.NewCommitSigAbsent()
+			continue
+		}
+		vote := &types.Vote{
+			Type:             tmproto.PrecommitType,
+			Height:           h.Height,
+			Round:            g.round,
+			BlockID:          blockID,
+			Timestamp:        h.Time,
+			ValidatorAddress: val.Address,
+			ValidatorIndex:   int32(i),
+		}
+		pb := vote.ToProto()
+		if err := key.SignVote(context.Background(), h.ChainID, pb); err != nil {
+			g.t.Fatal(err)
+		}
+		vote.Signature = pb.Signature
+		sigs[i] = vote.CommitSig()
+	}
+	return types.NewCommit(h.Height, g.round, blockID, sigs)
+}
+
+// staticSets returns n times the same validator set.
+func staticSets(vals *types.ValidatorSet, n int) []*types.ValidatorSet {
+	sets := make([]*types.ValidatorSet, n)
+	for i := range sets {
+		sets[i] = vals
+	}
+	return sets
+}
+
+// newTestClient returns a client of blocks at testNow.
+func newTestClient(t *testing.T, blocks []*types.LightBlock) *Client {
+	t.Helper()
+	c, err := NewClient(NewFixtures(blocks...), DefaultOptions(), testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
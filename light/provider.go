@@ -0,0 +1,161 @@
+// Package light is a light client core: starting from a trusted light block,
+// it verifies the headers of a chain up to a target height by adjacent or
+// skipping verification, bisecting whenever a skip cannot be trusted.
+package light
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tendermint/tendermint/types"
+
+	"goserver/rpc"
+)
+
+var (
+	// ErrLightBlockNotFound is returned by a Provider that has no light
+	// block at the requested height.
+	ErrLightBlockNotFound = errors.New("light block not found")
+	// ErrProvider is returned when a provider cannot be reached or answers
+	// with something other than an RPC response.
+	ErrProvider = errors.New("light block provider failed")
+)
+
+// Provider serves the light blocks of one chain. A height of 0 requests the
+// latest light block.
+type Provider interface {
+	LightBlock(ctx context.Context, height int64) (*types.LightBlock, error)
+}
+
+// Fixtures is a Provider that serves recorded light blocks by height, so
+// verification can run fully offline.
+type Fixtures map[int64]*types.LightBlock
+
+// NewFixtures indexes blocks by height.
+func NewFixtures(blocks ...*types.LightBlock) Fixtures {
+	f := make(Fixtures, len(blocks))
+	for _, lb := range blocks {
+		f[lb.Height] = lb
+	}
+	return f
+}
+
+// LightBlock implements Provider.
+func (f Fixtures) LightBlock(_ context.Context, height int64) (*types.LightBlock, error) {
+	if height == 0 {
+		for h := range f {
+			if h > height {
+				height = h
+			}
+		}
+	}
+	lb, ok := f[height]
+	if !ok {
+		return nil, fmt.Errorf("%w: height %d", ErrLightBlockNotFound, height)
+	}
+	return lb, nil
+}
+
+const (
+	// validatorsPerPage is the maximum page size of the /validators endpoint.
+	validatorsPerPage = 100
+	// maxResponseSize bounds the body read from one RPC call.
+	maxResponseSize = 16 << 20
+)
+
+// defaultClient is used by an HTTPProvider without a Client, so that a server
+// that never answers cannot hold a request forever.
+var defaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// HTTPProvider fetches light blocks from the /commit and /validators
+// endpoints of a tendermint RPC server. Client defaults to one with a 10s
+// timeout.
+type HTTPProvider struct {
+	URL    string
+	Client *http.Client
+}
+
+// LightBlock implements Provider.
+func (p *HTTPProvider) LightBlock(ctx context.Context, height int64) (*types.LightBlock, error) {
+	query := url.Values{}
+	if height != 0 {
+		query.Set("height", strconv.FormatInt(height, 10))
+	}
+	commit, err := p.get(ctx, "commit", query)
+	if err != nil {
+		return nil, err
+	}
+	sh, err := rpc.ParseCommit(commit)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		pages [][]byte
+		seen  int
+	)
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("height", strconv.FormatInt(sh.Height, 10))
+		query.Set("page", strconv.Itoa(page))
+		query.Set("per_page", strconv.Itoa(validatorsPerPage))
+		bz, err := p.get(ctx, "validators", query)
+		if err != nil {
+			return nil, err
+		}
+		_, vals, total, err := rpc.ParseValidators(bz)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, bz)
+		seen += len(vals)
+		if len(vals) == 0 || seen >= total {
+			break
+		}
+	}
+
+	return rpc.ParseLightBlock(commit, pages...)
+}
+
+func (p *HTTPProvider) get(ctx context.Context, endpoint string, query url.Values) ([]byte, error) {
+	u := strings.TrimSuffix(p.URL, "/") + "/" + endpoint + "?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := p.Client
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: fetch /%s: %v", ErrProvider, endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	bz, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: read /%s: %v", ErrProvider, endpoint, err)
+	}
+	if len(bz) > maxResponseSize {
+		return nil, fmt.Errorf("%w: read /%s: response larger than %d bytes", ErrProvider, endpoint, maxResponseSize)
+	}
+	// tendermint answers errors with a JSON-RPC error body, which rpc
+	// reports, so only fail here on a body that is not JSON
+	if resp.StatusCode != http.StatusOK && !isJSONObject(bz) {
+		return nil, fmt.Errorf("%w: fetch /%s: %s", ErrProvider, endpoint, resp.Status)
+	}
+	return bz, nil
+}
+
+func isJSONObject(bz []byte) bool {
+	return strings.HasPrefix(strings.TrimSpace(string(bz)), "{")
+}
@@ -0,0 +1,199 @@
+package light
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	tmmath "github.com/tendermint/tendermint/libs/math"
+	"github.com/tendermint/tendermint/types"
+
+	"goserver/message"
+	"server/headerTest/header"
+	"server/verifyValidator/validator"
+)
+
+var (
+	// ErrOldHeaderExpired is returned when the trusted header is older than
+	// the trusting period.
+	ErrOldHeaderExpired = errors.New("trusted header has expired")
+	// ErrNewValSetCantBeTrusted is returned when too little of the trusted
+	// validator set signed a non-adjacent header, so the verifier must
+	// bisect.
+	ErrNewValSetCantBeTrusted = errors.New("new validator set cannot be trusted")
+	// ErrInvalidHeader is returned when a light block is malformed or does
+	// not verify.
+	ErrInvalidHeader = errors.New("invalid header")
+	// ErrInvalidOptions is returned for unusable options or a client
+	// without a provider.
+	ErrInvalidOptions = errors.New("invalid light client options")
+)
+
+// Options are the security parameters of the light client.
+type Options struct {
+	// TrustingPeriod is how long a header stays trusted, which must be
+	// shorter than the chain's unbonding period.
+	TrustingPeriod time.Duration
+	// MaxClockDrift is how far in the future a header's time may be.
+	MaxClockDrift time.Duration
+	// TrustLevel is the fraction of a trusted validator set that must sign
+	// a non-adjacent header.
+	TrustLevel tmmath.Fraction
+}
+
+// DefaultOptions trusts headers for two weeks, allows 10s of clock drift and
+// uses the default 1/3 trust level.
+func DefaultOptions() Options {
+	return Options{
+		TrustingPeriod: 14 * 24 * time.Hour,
+		MaxClockDrift:  10 * time.Second,
+		TrustLevel:     message.DefaultTrustLevel,
+	}
+}
+
+// ValidateBasic checks that the options are usable.
+func (o Options) ValidateBasic() error {
+	if o.TrustingPeriod <= 0 {
+		return fmt.Errorf("%w: trusting period must be positive", ErrInvalidOptions)
+	}
+	if o.MaxClockDrift < 0 {
+		return fmt.Errorf("%w: max clock drift must not be negative", ErrInvalidOptions)
+	}
+	if err := message.ValidateTrustLevel(o.TrustLevel); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidOptions, err)
+	}
+	return nil
+}
+
+// Mode is how a header was verified.
+type Mode string
+
+const (
+	// ModeAdjacent verifies the header at the height after the trusted one.
+	ModeAdjacent Mode = "adjacent"
+	// ModeSkipping verifies a later header by trust level.
+	ModeSkipping Mode = "skipping"
+)
+
+// Step is one verified header of a trace.
+type Step struct {
+	Height              int64            `json:"height"`
+	Hash                tmbytes.HexBytes `json:"hash"`
+	Mode                Mode             `json:"mode"`
+	SignedVotingPower   int64            `json:"signed_voting_power"`
+	TotalVotingPower    int64            `json:"total_voting_power"`
+	OverlapVotingPower  int64            `json:"overlap_voting_power,omitempty"`
+	RequiredVotingPower int64            `json:"required_voting_power,omitempty"`
+}
+
+// HeaderExpired reports whether h is older than trustingPeriod at now.
+func HeaderExpired(h *types.Header, trustingPeriod time.Duration, now time.Time) bool {
+	return !h.Time.Add(trustingPeriod).After(now)
+}
+
+// Verify verifies untrusted from trusted at time now. If untrusted is at the
+// next height it must be linked to trusted and signed by +2/3 of its validator
+// set; otherwise more than the trust level of trusted's validator set must
+// also have signed it. Errors wrap ErrOldHeaderExpired,
+// ErrNewValSetCantBeTrusted or ErrInvalidHeader.
+func Verify(trusted, untrusted *types.LightBlock, opts Options, now time.Time) (*Step, error) {
+	if err := ValidateLightBlock(trusted); err != nil {
+		return nil, fmt.Errorf("trusted block: %w", err)
+	}
+	if HeaderExpired(trusted.Header, opts.TrustingPeriod, now) {
+		return nil, fmt.Errorf("%w: header %d from %v, trusting period %v",
+			ErrOldHeaderExpired, trusted.Height, trusted.Time, opts.TrustingPeriod)
+	}
+	if err := ValidateLightBlock(untrusted); err != nil {
+		return nil, err
+	}
+	if untrusted.ChainID != trusted.ChainID {
+		return nil, fmt.Errorf("%w: header %d is on chain %q, expected %q",
+			ErrInvalidHeader, untrusted.Height, untrusted.ChainID, trusted.ChainID)
+	}
+	if untrusted.Height <= trusted.Height {
+		return nil, fmt.Errorf("%w: header %d is not above trusted header %d",
+			ErrInvalidHeader, untrusted.Height, trusted.Height)
+	}
+	if !untrusted.Time.After(trusted.Time) {
+		return nil, fmt.Errorf("%w: header %d time %v is not after trusted header time %v",
+			ErrInvalidHeader, untrusted.Height, untrusted.Time, trusted.Time)
+	}
+	if limit := now.Add(opts.MaxClockDrift); untrusted.Time.After(limit) {
+		return nil, fmt.Errorf("%w: header %d time %v is after %v (now + max clock drift)",
+			ErrInvalidHeader, untrusted.Height, untrusted.Time, limit)
+	}
+
+	step := &Step{
+		Height: untrusted.Height,
+		Hash:   untrusted.Hash(),
+		Mode:   ModeSkipping,
+	}
+
+	if untrusted.Height == trusted.Height+1 {
+		step.Mode = ModeAdjacent
+		if err := header.VerifyAdjacentHeaders(trusted.Header, untrusted.Header); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidHeader, err)
+		}
+	} else {
+		res, err := message.VerifyCommitTrusting(trusted.ChainID, untrusted.Commit,
+			trusted.ValidatorSet, trusted.ValidatorsHash, opts.TrustLevel)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidHeader, err)
+		}
+		step.OverlapVotingPower = res.OverlapVotingPower
+		step.RequiredVotingPower = res.RequiredVotingPower
+		if !res.Trusted {
+			return step, fmt.Errorf("%w: header %d is signed by %d of the %d trusted voting power required",
+				ErrNewValSetCantBeTrusted, untrusted.Height, res.OverlapVotingPower, res.RequiredVotingPower)
+		}
+	}
+
+	res, err := message.VerifyCommitBatch(untrusted.ChainID, untrusted.Commit.BlockID, untrusted.Height,
+		untrusted.Commit, untrusted.ValidatorSet, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHeader, err)
+	}
+	step.SignedVotingPower = res.SignedVotingPower
+	step.TotalVotingPower = res.TotalVotingPower
+	if !res.QuorumReached {
+		return nil, fmt.Errorf("%w: header %d is signed by %d of %d voting power, need more than 2/3",
+			ErrInvalidHeader, untrusted.Height, res.SignedVotingPower, res.TotalVotingPower)
+	}
+	return step, nil
+}
+
+// ValidateLightBlock checks that lb is complete, that its commit is for its
+// header and that its validator set, whose addresses must match their keys,
+// hashes to the header's ValidatorsHash. It does not verify signatures.
+func ValidateLightBlock(lb *types.LightBlock) error {
+	if lb == nil || lb.SignedHeader == nil || lb.Header == nil || lb.Commit == nil {
+		return fmt.Errorf("%w: incomplete light block", ErrInvalidHeader)
+	}
+	if lb.ValidatorSet == nil || lb.ValidatorSet.IsNilOrEmpty() {
+		return fmt.Errorf("%w: light block %d has no validator set", ErrInvalidHeader, lb.Height)
+	}
+	if err := validator.CheckSet(lb.ValidatorSet.Validators); err != nil {
+		return fmt.Errorf("%w: light block %d: %v", ErrInvalidHeader, lb.Height, err)
+	}
+	if lb.Commit.Height != lb.Height {
+		return fmt.Errorf("%w: commit height %d does not match header height %d",
+			ErrInvalidHeader, lb.Commit.Height, lb.Height)
+	}
+	if hash := lb.Header.Hash(); !bytes.Equal(hash, lb.Commit.BlockID.Hash) {
+		return fmt.Errorf("%w: commit signs block %X, header %d hash is %X",
+			ErrInvalidHeader, lb.Commit.BlockID.Hash, lb.Height, hash)
+	}
+
+	valsHash, err := validator.Hash(lb.ValidatorSet.Validators)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidHeader, err)
+	}
+	if !bytes.Equal(valsHash, lb.ValidatorsHash) {
+		return fmt.Errorf("%w: validator set hash %X does not match header %d validators hash %X",
+			ErrInvalidHeader, valsHash, lb.Height, lb.ValidatorsHash)
+	}
+	return nil
+}
@@ -1,108 +1,131 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"encoding/hex"
+	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+
+	"goserver/store"
+)
 
-	"github.com/streadway/amqp"
+// trustedStore keeps the light blocks verified by light_verify. It is nil
+// until main opens it.
+var trustedStore store.Store
+
+var (
+	// lightRPCURLs are the only RPC endpoints light_verify may fetch light
+	// blocks from. None are allowed unless LIGHT_RPC_URLS lists them.
+	lightRPCURLs []string
+	// lightTimeout bounds one light_verify request, RPC calls included.
+	lightTimeout = 30 * time.Second
+	// lightTrustRoots are the light blocks, set by LIGHT_TRUST_ROOTS, that
+	// light_verify may save the blocks it verifies from when they are given
+	// inline rather than loaded from the trusted store.
+	lightTrustRoots []trustRoot
 )
 
-func main() {
-	conn, err := amqp.Dial("amqp:@localhost:5672/")
-	if err != nil {
-		log.Fatalf("failed to connect to RabbitMQ: %v", err)
-	}
-	defer conn.Close()
+// trustRoot identifies a light block trusted by configuration.
+type trustRoot struct {
+	ChainID string
+	Height  int64
+	Hash    tmbytes.HexBytes
+}
 
-	ch, err := conn.Channel()
-	if err != nil {
-		log.Fatalf("failed to open a channel: %v", err)
+// parseTrustRoots parses a comma-separated list of chain-id:height:hex-hash.
+func parseTrustRoots(s string) ([]trustRoot, error) {
+	var roots []trustRoot
+	for _, field := range strings.Split(s, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		// the chain ID may itself contain colons
+		parts := strings.Split(field, ":")
+		n := len(parts)
+		if n < 3 || parts[0] == "" {
+			return nil, fmt.Errorf("trust root %q is not chain-id:height:hash", field)
+		}
+		height, err := strconv.ParseInt(parts[n-2], 10, 64)
+		if err != nil || height <= 0 {
+			return nil, fmt.Errorf("trust root %q has a bad height", field)
+		}
+		hash, err := hex.DecodeString(parts[n-1])
+		if err != nil || len(hash) != tmhash.Size {
+			return nil, fmt.Errorf("trust root %q has a bad hash", field)
+		}
+		roots = append(roots, trustRoot{ChainID: strings.Join(parts[:n-2], ":"), Height: height, Hash: hash})
 	}
-	defer ch.Close()
+	return roots, nil
+}
 
-	q, err := ch.QueueDeclare(
-		"go_service_req", // queue name
-		false,            // durable
-		false,            // delete when unused
-		false,            // exclusive
-		false,            // no-wait
-		nil,              // arguments
-	)
-	if err != nil {
-		log.Fatalf("failed to declare a queue: %v", err)
+func main() {
+	storePath := os.Getenv("TRUSTED_STORE_PATH")
+	if storePath == "" {
+		storePath = "trusted_headers.db"
 	}
-
-	msgs, err := ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		true,   // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
+	fileStore, err := store.OpenFileStore(storePath)
 	if err != nil {
-		log.Fatalf("failed to register a consumer: %v", err)
+		log.Fatalf("failed to open trusted store: %v", err)
 	}
+	trustedStore = fileStore
 
-	forever := make(chan bool)
-
-	go func() {
-		for d := range msgs {
-			log.Printf("Received a message: %s", d.Body)
-
-			var req struct {
-				TypeService string `json:"type_service"`
-				Data        string `json:"data"`
-			}
-			if err := json.Unmarshal(d.Body, &req); err != nil {
-				log.Printf("failed to parse request: %v", err)
-				continue
-			}
-
-			res := struct {
-				TypeService string `json:"type_service"`
-				ResData     string `json:"res_data"`
-			}{
-				TypeService: req.TypeService,
-			}
-
-			// if req.TypeService == "1" {
-
-			// 	result := Message()
-			// 	if result == true {
-			// 		res.ResData = "true"
-
-			// 	} else {
-			// 		res.ResData = "false"
-			// 	}
-			// 	// convert bool to string
+	// LIGHT_RPC_URLS is a comma-separated list of allowed RPC endpoints
+	for _, u := range strings.Split(os.Getenv("LIGHT_RPC_URLS"), ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			lightRPCURLs = append(lightRPCURLs, u)
+		}
+	}
+	if s := os.Getenv("LIGHT_TIMEOUT"); s != "" {
+		if lightTimeout, err = time.ParseDuration(s); err != nil {
+			log.Fatalf("failed to parse LIGHT_TIMEOUT: %v", err)
+		}
+	}
+	if lightTrustRoots, err = parseTrustRoots(os.Getenv("LIGHT_TRUST_ROOTS")); err != nil {
+		log.Fatalf("failed to parse LIGHT_TRUST_ROOTS: %v", err)
+	}
 
-			// }
+	w := &worker{URL: os.Getenv("AMQP_URL"), MaxDowntime: 5 * time.Minute}
+	if w.URL == "" {
+		w.URL = "amqp:@localhost:5672/"
+	}
+	if s := os.Getenv("AMQP_MAX_DOWNTIME"); s != "" {
+		if w.MaxDowntime, err = time.ParseDuration(s); err != nil {
+			log.Fatalf("failed to parse AMQP_MAX_DOWNTIME: %v", err)
+		}
+	}
 
-			resBytes, err := json.Marshal(res)
-			if err != nil {
-				log.Printf("failed to serialize response: %v", err)
-				continue
+	// HEALTH_ADDR serves /healthz, which fails while RabbitMQ is down
+	if addr := os.Getenv("HEALTH_ADDR"); addr != "" {
+		http.HandleFunc("/healthz", func(rw http.ResponseWriter, _ *http.Request) {
+			if !w.Healthy() {
+				http.Error(rw, "unhealthy", http.StatusServiceUnavailable)
+				return
 			}
+			rw.Write([]byte("ok"))
+		})
+		go func() {
+			log.Printf("health check server stopped: %v", http.ListenAndServe(addr, nil))
+		}()
+	}
 
-			if err := ch.Publish(
-				"",               // exchange
-				"go_service_res", // routing key
-				false,            // mandatory
-				false,            // immediate
-				amqp.Publishing{
-					ContentType:   "application/json",
-					CorrelationId: d.CorrelationId,
-					Body:          resBytes,
-				},
-			); err != nil {
-				log.Printf("failed to publish response: %v", err)
-				continue
-			}
-		}
-	}()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	log.Printf(" [*] Waiting for messages. To exit press CTRL+C")
-	<-forever
+	log.Printf(" [*] Registered services: %v", RegisteredServices())
+	log.Printf(" [*] Connecting to RabbitMQ. To exit press CTRL+C")
+	err = w.Run(ctx)
+	fileStore.Close()
+	if err != nil {
+		log.Fatalf("giving up: %v", err)
+	}
 }
@@ -0,0 +1,118 @@
+package message
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/batch"
+	tmTypes "github.com/tendermint/tendermint/types"
+)
+
+// VerifyCommitBatch is VerifyCommit with the ed25519 and sr25519 signatures
+// checked in batches. The signatures are split across at most workers goroutines, or
+// GOMAXPROCS if workers is not positive, each of which verifies its share in
+// one batch and, only if the batch fails, checks them one by one to pinpoint
+// the bad ones. The result is the same as VerifyCommit's.
+func VerifyCommitBatch(chainID string, blockID tmTypes.BlockID, height int64,
+	commit *tmTypes.Commit, validatorSet *tmTypes.ValidatorSet, workers int) (*CommitResult, error) {
+	return verifyCommit(chainID, blockID, height, commit, validatorSet, func(chainID string, checks []*sigCheck) {
+		verifyBatch(chainID, checks, workers)
+	})
+}
+
+// minBatchSize is the smallest share of signatures worth a goroutine; below
+// it the batch setup costs more than it saves.
+const minBatchSize = 16
+
+func verifyBatch(chainID string, checks []*sigCheck, workers int) {
+	pending := make([]*sigCheck, 0, len(checks))
+	for _, c := range checks {
+		if c.err == "" {
+			pending = append(pending, c)
+		}
+	}
+
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if max := (len(pending) + minBatchSize - 1) / minBatchSize; workers > max {
+		workers = max
+	}
+	if workers <= 1 {
+		verifyShare(chainID, pending)
+		return
+	}
+
+	size := (len(pending) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(pending); start += size {
+		end := start + size
+		if end > len(pending) {
+			end = len(pending)
+		}
+		wg.Add(1)
+		go func(share []*sigCheck) {
+			defer wg.Done()
+			verifyShare(chainID, share)
+		}(pending[start:end])
+	}
+	wg.Wait()
+}
+
+// keyBatch is the batch of signatures of one key type in a share.
+type keyBatch struct {
+	bv     crypto.BatchVerifier
+	checks []*sigCheck
+}
+
+// verifyShare batch-verifies the signatures of checks by key type, verifying
+// the key types without batch support, such as secp256k1, one at a time.
+func verifyShare(chainID string, checks []*sigCheck) {
+	batches := make(map[string]*keyBatch)
+
+	for _, c := range checks {
+		if err := checkKeyType(c.pubKey); err != nil {
+			c.err = err.Error()
+			continue
+		}
+		kb, ok := batches[c.pubKey.Type()]
+		if !ok {
+			bv, ok := batch.CreateBatchVerifier(c.pubKey)
+			if !ok {
+				verifyEach(chainID, []*sigCheck{c})
+				continue
+			}
+			kb = &keyBatch{bv: bv}
+			batches[c.pubKey.Type()] = kb
+		}
+		signBytes, err := VoteSignBytes(chainID, c.vote)
+		if err != nil {
+			c.err = err.Error()
+			continue
+		}
+
+		if err := kb.bv.Add(c.pubKey, signBytes, c.vote.Signature); err != nil {
+			c.err = err.Error()
+			continue
+		}
+		kb.checks = append(kb.checks, c)
+	}
+
+	for _, kb := range batches {
+		if len(kb.checks) == 0 {
+			continue
+		}
+		// when the batch equation fails, the verifier checks each signature
+		// on its own and reports which are valid
+		ok, valid := kb.bv.Verify()
+		if ok {
+			continue
+		}
+		for i, c := range kb.checks {
+			if !valid[i] {
+				c.err = "signature does not match public key"
+			}
+		}
+	}
+}
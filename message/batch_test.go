@@ -0,0 +1,184 @@
+package message
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	"github.com/tendermint/tendermint/crypto/sr25519"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmTypes "github.com/tendermint/tendermint/types"
+)
+
+const (
+	benchChainID    = "Oraichain"
+	benchHeight     = 10320459
+	benchValidators = 150
+)
+
+// ed25519Keys returns n new ed25519 keys.
+func ed25519Keys(n int) []crypto.PrivKey {
+	keys := make([]crypto.PrivKey, n)
+	for i := range keys {
+		keys[i] = ed25519.GenPrivKey()
+	}
+	return keys
+}
+
+// mixedKeys returns n new keys cycling through ed25519, sr25519 and
+// secp256k1.
+func mixedKeys(n int) []crypto.PrivKey {
+	keys := make([]crypto.PrivKey, n)
+	for i := range keys {
+		switch i % 3 {
+		case 0:
+			keys[i] = ed25519.GenPrivKey()
+		case 1:
+			keys[i] = sr25519.GenPrivKey()
+		default:
+			keys[i] = secp256k1.GenPrivKey()
+		}
+	}
+	return keys
+}
+
+// syntheticCommit returns a commit of height signed by every key, each the
+// key of a validator of a new set.
+func syntheticCommit(tb testing.TB, keys []crypto.PrivKey, height int64) (tmTypes.BlockID, *tmTypes.Commit, *tmTypes.ValidatorSet) {
+	tb.Helper()
+	byAddr := make(map[string]crypto.PrivKey, len(keys))
+	vals := make([]*tmTypes.Validator, len(keys))
+	for i, key := range keys {
+		byAddr[string(key.PubKey().Address())] = key
+		vals[i] = tmTypes.NewValidator(key.PubKey(), int64(1000+i))
+	}
+	valSet := tmTypes.NewValidatorSet(vals)
+
+	blockID := tmTypes.BlockID{
+		Hash:          tmhash.Sum([]byte("block")),
+		PartSetHeader: tmTypes.PartSetHeader{Total: 1, Hash: tmhash.Sum([]byte("parts"))},
+	}
+	now := time.Now().UTC()
+
+	sigs := make([]tmTypes.CommitSig, len(keys))
+	for i, val := range valSet.Validators {
+		vote := &tmTypes.Vote{
+			Type:             tmproto.PrecommitType,
+			Height:           height,
+			BlockID:          blockID,
+			Timestamp:        now,
+			ValidatorAddress: val.Address,
+			ValidatorIndex:   int32(i),
+		}
+		sig, err := byAddr[string(val.Address)].Sign(tmTypes.VoteSignBytes(benchChainID, vote.ToProto()))
+		if err != nil {
+			tb.Fatal(err)
+		}
+		sigs[i] = tmTypes.CommitSig{
+			BlockIDFlag:      tmTypes.BlockIDFlagCommit,
+			ValidatorAddress: val.Address,
+			Timestamp:        now,
+			Signature:        sig,
+		}
+	}
+
+	return blockID, &tmTypes.Commit{Height: height, BlockID: blockID, Signatures: sigs}, valSet
+}
+
+// reportSigsPerSec reports the signatures of b.N commits of n validators
+// checked per second since start.
+func reportSigsPerSec(b *testing.B, n int, start time.Time) {
+	b.ReportMetric(float64(n*b.N)/time.Since(start).Seconds(), "sigs/s")
+}
+
+func TestVerifyCommitBatchFallback(t *testing.T) {
+	const n = 48
+	tests := []struct {
+		name  string
+		keys  []crypto.PrivKey
+		bad   []int
+		valid bool
+	}{
+		{"none bad", ed25519Keys(n), nil, true},
+		{"one bad", ed25519Keys(n), []int{17}, true},
+		{"several bad", ed25519Keys(n), []int{0, 5, 31, 47}, true},
+		{"mixed keys, none bad", mixedKeys(n), nil, true},
+		// one bad signature of each key type
+		{"mixed keys, several bad", mixedKeys(n), []int{3, 10, 20}, true},
+		{"mixed keys, most bad", mixedKeys(n), []int{1, 2, 4, 5, 7, 8, 10, 11, 13, 14, 16, 17, 19, 20, 22, 23, 25}, false},
+	}
+	for _, tc := range tests {
+		blockID, commit, valSet := syntheticCommit(t, tc.keys, benchHeight)
+		for _, i := range tc.bad {
+			sig := append([]byte(nil), commit.Signatures[i].Signature...)
+			sig[0] ^= 0xff
+			commit.Signatures[i].Signature = sig
+		}
+		// 3 workers split the signatures into shares of 16
+		for _, workers := range []int{1, 3} {
+			t.Run(fmt.Sprintf("%s/workers=%d", tc.name, workers), func(t *testing.T) {
+				res, err := VerifyCommitBatch(benchChainID, blockID, benchHeight, commit, valSet, workers)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var bad []int
+				for _, v := range res.BadSignature {
+					bad = append(bad, v.Index)
+					if v.Error == "" {
+						t.Errorf("bad signature %d has no error", v.Index)
+					}
+				}
+				if !reflect.DeepEqual(bad, tc.bad) {
+					t.Errorf("bad signatures %v, want %v", bad, tc.bad)
+				}
+				if len(res.Signed) != n-len(tc.bad) {
+					t.Errorf("%d signed, want %d", len(res.Signed), n-len(tc.bad))
+				}
+				if res.QuorumReached != tc.valid {
+					t.Errorf("quorum reached %v, want %v", res.QuorumReached, tc.valid)
+				}
+
+				single, err := VerifyCommit(benchChainID, blockID, benchHeight, commit, valSet)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(res, single) {
+					t.Errorf("batch result %+v differs from VerifyCommit's %+v", res, single)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkVerifyCommitSingle(b *testing.B) {
+	blockID, commit, valSet := syntheticCommit(b, ed25519Keys(benchValidators), benchHeight)
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		if _, err := VerifyCommit(benchChainID, blockID, benchHeight, commit, valSet); err != nil {
+			b.Fatal(err)
+		}
+	}
+	reportSigsPerSec(b, benchValidators, start)
+}
+
+func BenchmarkVerifyCommitBatch(b *testing.B) {
+	blockID, commit, valSet := syntheticCommit(b, ed25519Keys(benchValidators), benchHeight)
+	// 0 workers is GOMAXPROCS
+	for _, workers := range []int{1, 2, 4, 0} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				if _, err := VerifyCommitBatch(benchChainID, blockID, benchHeight, commit, valSet, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+			reportSigsPerSec(b, benchValidators, start)
+		})
+	}
+}
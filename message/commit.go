@@ -0,0 +1,245 @@
+package message
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/tendermint/tendermint/crypto"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	tmmath "github.com/tendermint/tendermint/libs/math"
+	protoTypes "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmTypes "github.com/tendermint/tendermint/types"
+
+	"server/verifyValidator/validator"
+)
+
+// CommitValidator is one validator's entry in a CommitResult.
+type CommitValidator struct {
+	Index       int              `json:"index"`
+	Address     tmbytes.HexBytes `json:"address"`
+	VotingPower int64            `json:"voting_power"`
+	Error       string           `json:"error,omitempty"`
+}
+
+// CommitResult is the vote-by-vote audit of a commit produced by VerifyCommit.
+type CommitResult struct {
+	Height            int64             `json:"height"`
+	TotalVotingPower  int64             `json:"total_voting_power"`
+	SignedVotingPower int64             `json:"signed_voting_power"`
+	QuorumReached     bool              `json:"quorum_reached"`
+	Signed            []CommitValidator `json:"signed"`
+	Nil               []CommitValidator `json:"nil"`
+	Absent            []CommitValidator `json:"absent"`
+	BadSignature      []CommitValidator `json:"bad_signature"`
+}
+
+// VerifyCommit checks every signature of commit against validatorSet, which
+// must be the set that signed it, in the same order. The quorum is reached if
+// the validators that signed blockID hold more than 2/3 of the total voting
+// power; nil votes are verified but not counted.
+func VerifyCommit(chainID string, blockID tmTypes.BlockID, height int64,
+	commit *tmTypes.Commit, validatorSet *tmTypes.ValidatorSet) (*CommitResult, error) {
+	return verifyCommit(chainID, blockID, height, commit, validatorSet, verifyEach)
+}
+
+// sigCheck is a commit signature to verify. err is set once it failed.
+type sigCheck struct {
+	entry    CommitValidator
+	forBlock bool
+	vote     *protoTypes.Vote
+	pubKey   crypto.PubKey
+	err      string
+}
+
+// verifyEach verifies the pending checks one at a time.
+func verifyEach(chainID string, checks []*sigCheck) {
+	for _, c := range checks {
+		if c.err != "" {
+			continue
+		}
+		if result := VerifyVote(chainID, c.vote, c.pubKey, nil); !result.Valid() {
+			c.err = result.Error
+		}
+	}
+}
+
+func verifyCommit(chainID string, blockID tmTypes.BlockID, height int64,
+	commit *tmTypes.Commit, validatorSet *tmTypes.ValidatorSet,
+	verify func(chainID string, checks []*sigCheck)) (*CommitResult, error) {
+	if err := checkValidatorSet(validatorSet); err != nil {
+		return nil, err
+	}
+	if commit == nil {
+		return nil, fmt.Errorf("%w: nil commit", ErrInvalidCommit)
+	}
+	if len(commit.Signatures) != validatorSet.Size() {
+		return nil, fmt.Errorf("%w: commit has %d signatures, validator set has %d validators",
+			ErrInvalidValidatorSet, len(commit.Signatures), validatorSet.Size())
+	}
+	if commit.Height != height {
+		return nil, fmt.Errorf("%w: commit height %d does not match expected height %d",
+			ErrInvalidCommit, commit.Height, height)
+	}
+	if !blockID.Equals(commit.BlockID) {
+		return nil, fmt.Errorf("%w: commit signs block %v, expected %v", ErrHashMismatch, commit.BlockID, blockID)
+	}
+
+	res := &CommitResult{
+		Height:           height,
+		TotalVotingPower: validatorSet.TotalVotingPower(),
+	}
+
+	checks := make([]*sigCheck, 0, len(commit.Signatures))
+	for idx, commitSig := range commit.Signatures {
+		val := validatorSet.Validators[idx]
+		entry := CommitValidator{
+			Index:       idx,
+			Address:     val.Address,
+			VotingPower: val.VotingPower,
+		}
+
+		if commitSig.Absent() {
+			res.Absent = append(res.Absent, entry)
+			continue
+		}
+
+		c := &sigCheck{entry: entry, forBlock: commitSig.ForBlock(), pubKey: val.PubKey}
+		if !bytes.Equal(commitSig.ValidatorAddress, val.Address) {
+			c.err = fmt.Sprintf("signature is from %v, expected validator %v", commitSig.ValidatorAddress, val.Address)
+		} else {
+			c.vote = commit.GetVote(int32(idx)).ToProto()
+		}
+		checks = append(checks, c)
+	}
+
+	verify(chainID, checks)
+
+	for _, c := range checks {
+		switch {
+		case c.err != "":
+			c.entry.Error = c.err
+			res.BadSignature = append(res.BadSignature, c.entry)
+		case c.forBlock:
+			res.SignedVotingPower += c.entry.VotingPower
+			res.Signed = append(res.Signed, c.entry)
+		default:
+			res.Nil = append(res.Nil, c.entry)
+		}
+	}
+
+	// total voting power is capped to 1/8th of max int64, so this cannot overflow
+	res.QuorumReached = res.SignedVotingPower*3 > res.TotalVotingPower*2
+
+	return res, nil
+}
+
+// DefaultTrustLevel is the fraction of a trusted validator set's voting power
+// that must sign a commit for VerifyCommitTrusting to accept it.
+var DefaultTrustLevel = tmmath.Fraction{Numerator: 1, Denominator: 3}
+
+// TrustingResult is the result of VerifyCommitTrusting.
+type TrustingResult struct {
+	Height              int64             `json:"height"`
+	TrustLevel          tmmath.Fraction   `json:"trust_level"`
+	TrustedVotingPower  int64             `json:"trusted_voting_power"`
+	RequiredVotingPower int64             `json:"required_voting_power"`
+	OverlapVotingPower  int64             `json:"overlap_voting_power"`
+	Trusted             bool              `json:"trusted"`
+	Signed              []CommitValidator `json:"signed"`
+	BadSignature        []CommitValidator `json:"bad_signature"`
+}
+
+// ValidateTrustLevel checks that trustLevel is within [1/3, 1).
+func ValidateTrustLevel(trustLevel tmmath.Fraction) error {
+	if trustLevel.Denominator == 0 ||
+		trustLevel.Numerator*3 < trustLevel.Denominator ||
+		trustLevel.Numerator >= trustLevel.Denominator {
+		return fmt.Errorf("%w: trust level must be within [1/3, 1), given %v", ErrInvalidTrustLevel, trustLevel)
+	}
+	return nil
+}
+
+// VerifyCommitTrusting checks that validators of an older, trusted set signed
+// commit with more than trustLevel of the trusted set's voting power. It is
+// used for non-adjacent updates, so signatures are matched to trusted
+// validators by address rather than by index. If trustedValidatorsHash is not
+// empty, trustedVals must hash to it.
+func VerifyCommitTrusting(chainID string, commit *tmTypes.Commit, trustedVals *tmTypes.ValidatorSet,
+	trustedValidatorsHash []byte, trustLevel tmmath.Fraction) (*TrustingResult, error) {
+	if err := checkValidatorSet(trustedVals); err != nil {
+		return nil, fmt.Errorf("trusted validator set: %w", err)
+	}
+	if commit == nil {
+		return nil, fmt.Errorf("%w: nil commit", ErrInvalidCommit)
+	}
+	if err := ValidateTrustLevel(trustLevel); err != nil {
+		return nil, err
+	}
+	if len(trustedValidatorsHash) > 0 {
+		hash, err := validator.Hash(trustedVals.Validators)
+		if err != nil {
+			return nil, fmt.Errorf("hash trusted validator set: %w", err)
+		}
+		if !bytes.Equal(hash, trustedValidatorsHash) {
+			return nil, fmt.Errorf("%w: trusted validator set hash %X does not match %X", ErrHashMismatch, hash, trustedValidatorsHash)
+		}
+	}
+
+	total := trustedVals.TotalVotingPower()
+	required, overflow := safeMulDiv(total, int64(trustLevel.Numerator), int64(trustLevel.Denominator))
+	if overflow {
+		return nil, fmt.Errorf("%w: int64 overflow while calculating required voting power", ErrInvalidValidatorSet)
+	}
+
+	res := &TrustingResult{
+		Height:              commit.Height,
+		TrustLevel:          trustLevel,
+		TrustedVotingPower:  total,
+		RequiredVotingPower: required,
+	}
+
+	seen := make(map[int32]int, len(commit.Signatures))
+	for idx, commitSig := range commit.Signatures {
+		if !commitSig.ForBlock() {
+			continue
+		}
+
+		valIdx, val := trustedVals.GetByAddress(commitSig.ValidatorAddress)
+		if val == nil {
+			continue
+		}
+		if first, ok := seen[valIdx]; ok {
+			return nil, fmt.Errorf("%w: double vote from %v (#%d and #%d)", ErrInvalidCommit, val.Address, first, idx)
+		}
+		seen[valIdx] = idx
+
+		entry := CommitValidator{
+			Index:       idx,
+			Address:     val.Address,
+			VotingPower: val.VotingPower,
+		}
+
+		vote := commit.GetVote(int32(idx)).ToProto()
+		if result := VerifyVote(chainID, vote, val.PubKey, commitSig.Signature); !result.Valid() {
+			entry.Error = result.Error
+			res.BadSignature = append(res.BadSignature, entry)
+			continue
+		}
+
+		res.OverlapVotingPower += val.VotingPower
+		res.Signed = append(res.Signed, entry)
+	}
+
+	res.Trusted = res.OverlapVotingPower > required
+
+	return res, nil
+}
+
+// safeMulDiv returns a*b/c, reporting whether a*b overflowed.
+func safeMulDiv(a, b, c int64) (int64, bool) {
+	if a != 0 && b > math.MaxInt64/a {
+		return 0, true
+	}
+	return a * b / c, false
+}
@@ -0,0 +1,66 @@
+package message
+
+import (
+	"errors"
+	"testing"
+
+	tmmath "github.com/tendermint/tendermint/libs/math"
+	tmTypes "github.com/tendermint/tendermint/types"
+)
+
+func TestVerifyCommitErrors(t *testing.T) {
+	const height = 10
+	blockID, commit, vals := syntheticCommit(t, ed25519Keys(4), height)
+	smaller, _ := genValSet(3)
+	otherBlock := blockID
+	otherBlock.Hash = make([]byte, len(blockID.Hash))
+
+	tests := []struct {
+		name    string
+		blockID tmTypes.BlockID
+		height  int64
+		commit  *tmTypes.Commit
+		vals    *tmTypes.ValidatorSet
+		want    error
+	}{
+		{"nil commit", blockID, height, nil, vals, ErrInvalidCommit},
+		{"nil validator set", blockID, height, commit, nil, ErrInvalidValidatorSet},
+		{"set of another size", blockID, height, commit, smaller, ErrInvalidValidatorSet},
+		{"other height", blockID, height + 1, commit, vals, ErrInvalidCommit},
+		{"other block", otherBlock, height, commit, vals, ErrHashMismatch},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := VerifyCommit(benchChainID, tc.blockID, tc.height, tc.commit, tc.vals)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestVerifyCommitTrustingErrors(t *testing.T) {
+	_, commit, vals := syntheticCommit(t, ed25519Keys(4), 10)
+	other, _ := genValSet(4)
+
+	tests := []struct {
+		name       string
+		commit     *tmTypes.Commit
+		hash       []byte
+		trustLevel tmmath.Fraction
+		want       error
+	}{
+		{"valid", commit, vals.Hash(), DefaultTrustLevel, nil},
+		{"nil commit", nil, nil, DefaultTrustLevel, ErrInvalidCommit},
+		{"trusted set of another header", commit, other.Hash(), DefaultTrustLevel, ErrHashMismatch},
+		{"trust level below 1/3", commit, nil, tmmath.Fraction{Numerator: 1, Denominator: 4}, ErrInvalidTrustLevel},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := VerifyCommitTrusting(benchChainID, tc.commit, vals, tc.hash, tc.trustLevel)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package message
+
+import (
+	"errors"
+
+	"server/verifyValidator/validator"
+)
+
+// Errors returned, wrapped, by the verification functions, so callers can
+// tell malformed input from a failed verification with errors.Is.
+var (
+	// ErrInvalidBlockID is a block ID or part set header that fails its
+	// basic validation.
+	ErrInvalidBlockID = errors.New("invalid block ID")
+	// ErrBadEncoding is input that cannot be decoded or encoded, such as bad
+	// hex, base64 or protobuf.
+	ErrBadEncoding = errors.New("bad encoding")
+	// ErrSignatureMismatch is a signature that does not verify against the
+	// public key or the expected signer.
+	ErrSignatureMismatch = errors.New("signature mismatch")
+	// ErrUnsupportedKeyType is a public key of a type other than ed25519,
+	// secp256k1 or sr25519, or of the wrong length.
+	ErrUnsupportedKeyType = validator.ErrUnsupportedKeyType
+	// ErrWrongProposer is a proposal signed by a validator that is not the
+	// proposer of its round.
+	ErrWrongProposer = errors.New("wrong proposer")
+	// ErrInvalidPOLRound is a proposal proof-of-lock round that is neither
+	// -1 nor below the proposal's round.
+	ErrInvalidPOLRound = errors.New("invalid POL round")
+	// ErrInvalidRound is a negative round, or one too high to derive its
+	// proposer.
+	ErrInvalidRound = errors.New("invalid round")
+	// ErrHashMismatch is a validator set or header whose hash is not the one
+	// it is committed to, or a commit for another block than the expected
+	// one.
+	ErrHashMismatch = errors.New("hash mismatch")
+	// ErrInvalidCommit is a commit that is missing, of another height than
+	// the expected one, or that holds two votes of one validator.
+	ErrInvalidCommit = errors.New("invalid commit")
+	// ErrInvalidTrustLevel is a trust level outside [1/3, 1).
+	ErrInvalidTrustLevel = errors.New("invalid trust level")
+	// ErrInvalidEvidence is a pair of votes that is not a double-sign of one
+	// validator.
+	ErrInvalidEvidence = errors.New("invalid evidence")
+	// ErrNotInSet is a validator missing from the validator set.
+	ErrNotInSet = validator.ErrNotInSet
+	// ErrInvalidValidatorSet is a validator set that is empty, holds a nil
+	// validator or one without a key, or whose voting power is out of
+	// range.
+	ErrInvalidValidatorSet = validator.ErrInvalidValidatorSet
+)
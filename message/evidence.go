@@ -0,0 +1,128 @@
+package message
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	protoTypes "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmTypes "github.com/tendermint/tendermint/types"
+
+	"server/verifyValidator/validator"
+)
+
+// DuplicateVote is a verified double-sign: the DuplicateVoteEvidence along
+// with its protobuf encoding, ready to be submitted to a full node or a
+// contract.
+type DuplicateVote struct {
+	Height           int64            `json:"height"`
+	Round            int32            `json:"round"`
+	Type             string           `json:"type"`
+	ValidatorAddress tmbytes.HexBytes `json:"validator_address"`
+	ValidatorPower   int64            `json:"validator_power"`
+	TotalVotingPower int64            `json:"total_voting_power"`
+	BlockIDA         tmTypes.BlockID  `json:"block_id_a"`
+	BlockIDB         tmTypes.BlockID  `json:"block_id_b"`
+	Timestamp        time.Time        `json:"timestamp"`
+	Hash             tmbytes.HexBytes `json:"hash"`
+	Proto            []byte           `json:"proto"`
+
+	Evidence *tmTypes.DuplicateVoteEvidence `json:"-"`
+}
+
+// VerifyDuplicateVote checks that voteA and voteB are votes of the same
+// validator of validatorSet, for the same height, round and type but
+// different blocks, and that both signatures are valid on chainID. If
+// validatorsHash is not empty, validatorSet must hash to it, tying the set to
+// a header. blockTime is the time of the block at the votes' height, which the
+// evidence expires from.
+func VerifyDuplicateVote(chainID string, voteA, voteB *protoTypes.Vote,
+	validatorSet *tmTypes.ValidatorSet, validatorsHash []byte, blockTime time.Time) (*DuplicateVote, error) {
+	if voteA == nil || voteB == nil {
+		return nil, fmt.Errorf("%w: missing vote", ErrInvalidEvidence)
+	}
+	if err := checkValidatorSet(validatorSet); err != nil {
+		return nil, err
+	}
+	if len(validatorsHash) > 0 {
+		hash, err := validator.Hash(validatorSet.Validators)
+		if err != nil {
+			return nil, fmt.Errorf("hash validator set: %w", err)
+		}
+		if !bytes.Equal(hash, validatorsHash) {
+			return nil, fmt.Errorf("%w: validator set hash %X does not match %X", ErrHashMismatch, hash, validatorsHash)
+		}
+	}
+	if voteA.Height != voteB.Height || voteA.Round != voteB.Round || voteA.Type != voteB.Type {
+		return nil, fmt.Errorf("%w: votes are for different steps: %d/%d/%v and %d/%d/%v",
+			ErrInvalidEvidence, voteA.Height, voteA.Round, voteA.Type, voteB.Height, voteB.Round, voteB.Type)
+	}
+	if !bytes.Equal(voteA.ValidatorAddress, voteB.ValidatorAddress) {
+		return nil, fmt.Errorf("%w: votes are from different validators %X and %X",
+			ErrInvalidEvidence, voteA.ValidatorAddress, voteB.ValidatorAddress)
+	}
+	if voteA.ValidatorIndex != voteB.ValidatorIndex {
+		return nil, fmt.Errorf("%w: votes have different validator indexes %d and %d",
+			ErrInvalidEvidence, voteA.ValidatorIndex, voteB.ValidatorIndex)
+	}
+
+	a, err := tmTypes.VoteFromProto(voteA)
+	if err != nil {
+		return nil, fmt.Errorf("%w: vote A: %v", ErrInvalidEvidence, err)
+	}
+	b, err := tmTypes.VoteFromProto(voteB)
+	if err != nil {
+		return nil, fmt.Errorf("%w: vote B: %v", ErrInvalidEvidence, err)
+	}
+	if a.BlockID.Equals(b.BlockID) {
+		return nil, fmt.Errorf("%w: both votes are for block %v", ErrInvalidEvidence, a.BlockID)
+	}
+
+	idx, val := validatorSet.GetByAddress(voteA.ValidatorAddress)
+	if val == nil {
+		return nil, fmt.Errorf("%w: validator %X", ErrNotInSet, voteA.ValidatorAddress)
+	}
+	if idx != voteA.ValidatorIndex {
+		return nil, fmt.Errorf("%w: validator %X is at index %d, votes claim index %d",
+			ErrInvalidEvidence, val.Address, idx, voteA.ValidatorIndex)
+	}
+	// the evidence blames val.Address, so it must be the signing key's
+	if err := validator.CheckAddress(val.Address, val.PubKey); err != nil {
+		return nil, err
+	}
+
+	if err := VerifyVote(chainID, voteA, val.PubKey, nil).Err(); err != nil {
+		return nil, fmt.Errorf("vote A: %w", err)
+	}
+	if err := VerifyVote(chainID, voteB, val.PubKey, nil).Err(); err != nil {
+		return nil, fmt.Errorf("vote B: %w", err)
+	}
+
+	ev, err := tmTypes.NewDuplicateVoteEvidence(a, b, blockTime, validatorSet)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvidence, err)
+	}
+	if err := ev.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvidence, err)
+	}
+	proto, err := ev.ToProto().Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("encode evidence: %w", err)
+	}
+
+	return &DuplicateVote{
+		Height:           ev.VoteA.Height,
+		Round:            ev.VoteA.Round,
+		Type:             ev.VoteA.Type.String(),
+		ValidatorAddress: val.Address,
+		ValidatorPower:   ev.ValidatorPower,
+		TotalVotingPower: ev.TotalVotingPower,
+		BlockIDA:         ev.VoteA.BlockID,
+		BlockIDB:         ev.VoteB.BlockID,
+		Timestamp:        ev.Timestamp,
+		Hash:             ev.Hash(),
+		Proto:            proto,
+		Evidence:         ev,
+	}, nil
+}
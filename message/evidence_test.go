@@ -0,0 +1,89 @@
+package message
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmTypes "github.com/tendermint/tendermint/types"
+
+	"server/verifyValidator/validator"
+)
+
+// signedVote returns a precommit for the block hashing blockName by the
+// validator at index of vals, signed with key.
+func signedVote(t *testing.T, key crypto.PrivKey, vals *tmTypes.ValidatorSet, index int32, blockName string) *tmproto.Vote {
+	t.Helper()
+	vote := &tmTypes.Vote{
+		Type:   tmproto.PrecommitType,
+		Height: 10,
+		BlockID: tmTypes.BlockID{
+			Hash:          tmhash.Sum([]byte(blockName)),
+			PartSetHeader: tmTypes.PartSetHeader{Total: 1, Hash: tmhash.Sum([]byte("parts " + blockName))},
+		},
+		Timestamp:        time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		ValidatorAddress: vals.Validators[index].Address,
+		ValidatorIndex:   index,
+	}
+	pb := vote.ToProto()
+	sig, err := key.Sign(tmTypes.VoteSignBytes(testChainID, pb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pb.Signature = sig
+	return pb
+}
+
+func TestVerifyDuplicateVote(t *testing.T) {
+	vals, keys := genValSet(4)
+	blockTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	voteA := signedVote(t, keys[1], vals, 1, "a")
+	voteB := signedVote(t, keys[1], vals, 1, "b")
+
+	ev, err := VerifyDuplicateVote(testChainID, voteA, voteB, vals, vals.Hash(), blockTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.ValidatorAddress.String() != vals.Validators[1].Address.String() {
+		t.Errorf("evidence against %v, want %v", ev.ValidatorAddress, vals.Validators[1].Address)
+	}
+	if ev.TotalVotingPower != 40 || ev.ValidatorPower != 10 {
+		t.Errorf("power %d of %d, want 10 of 40", ev.ValidatorPower, ev.TotalVotingPower)
+	}
+
+	other, _ := genValSet(4)
+	if _, err := VerifyDuplicateVote(testChainID, voteA, voteB, vals, other.Hash(), blockTime); !errors.Is(err, ErrHashMismatch) {
+		t.Errorf("set of another header: got %v, want %v", err, ErrHashMismatch)
+	}
+	if _, err := VerifyDuplicateVote(testChainID, voteA, voteA, vals, nil, blockTime); !errors.Is(err, ErrInvalidEvidence) {
+		t.Errorf("same vote twice: got %v, want %v", err, ErrInvalidEvidence)
+	}
+	if _, err := VerifyDuplicateVote(testChainID, voteA, voteB, other, nil, blockTime); !errors.Is(err, ErrNotInSet) {
+		t.Errorf("validator of another set: got %v, want %v", err, ErrNotInSet)
+	}
+}
+
+func TestVerifyDuplicateVoteForgedAddress(t *testing.T) {
+	vals, _ := genValSet(4)
+	victim := vals.Validators[1]
+
+	// the attacker's key listed under the victim's address
+	attacker := ed25519.GenPrivKey()
+	forged := vals.Copy()
+	forged.Validators[1] = &tmTypes.Validator{
+		Address:     victim.Address,
+		PubKey:      attacker.PubKey(),
+		VotingPower: victim.VotingPower,
+	}
+	voteA := signedVote(t, attacker, forged, 1, "a")
+	voteB := signedVote(t, attacker, forged, 1, "b")
+
+	blockTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := VerifyDuplicateVote(testChainID, voteA, voteB, forged, nil, blockTime); !errors.Is(err, validator.ErrAddressMismatch) {
+		t.Fatalf("got %v, want %v", err, validator.ErrAddressMismatch)
+	}
+}
@@ -0,0 +1,27 @@
+package message
+
+import (
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	tmTypes "github.com/tendermint/tendermint/types"
+)
+
+const testChainID = "test-chain"
+
+// genValSet returns a validator set of n ed25519 keys and the key of each
+// validator, in the set's order.
+func genValSet(n int) (*tmTypes.ValidatorSet, []crypto.PrivKey) {
+	byAddr := make(map[string]crypto.PrivKey, n)
+	vals := make([]*tmTypes.Validator, n)
+	for i := range vals {
+		key := ed25519.GenPrivKey()
+		byAddr[string(key.PubKey().Address())] = key
+		vals[i] = tmTypes.NewValidator(key.PubKey(), 10)
+	}
+	set := tmTypes.NewValidatorSet(vals)
+	keys := make([]crypto.PrivKey, n)
+	for i, val := range set.Validators {
+		keys[i] = byAddr[string(val.Address)]
+	}
+	return set, keys
+}
@@ -1,21 +1,18 @@
 package message
 
 import (
-	"crypto/ed25519"
 	"encoding/base64"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/mihongtech/tendermint/libs/protoio"
+	"github.com/tendermint/tendermint/crypto/ed25519"
 	protoTypes "github.com/tendermint/tendermint/proto/tendermint/types"
 	tmTypes "github.com/tendermint/tendermint/types"
 )
 
-func First[T, U any](val T, _ U) T {
-	return val
-}
-
 // FromProto sets a protobuf PartSetHeader to the given pointer
 func PartSetHeaderFromProto(ppsh *protoTypes.PartSetHeader) (*tmTypes.PartSetHeader, error) {
 	if ppsh == nil {
@@ -28,27 +25,34 @@ func PartSetHeaderFromProto(ppsh *protoTypes.PartSetHeader) (*tmTypes.PartSetHea
 	return psh, psh.ValidateBasic()
 }
 
+// BlockIDFromProto converts a protobuf BlockID, failing with
+// ErrInvalidBlockID if it is not valid.
 func BlockIDFromProto(bID *protoTypes.BlockID) (*tmTypes.BlockID, error) {
 	if bID == nil {
-		return nil, errors.New("nil BlockID")
+		return nil, fmt.Errorf("%w: nil BlockID", ErrInvalidBlockID)
 	}
 
 	blockID := new(tmTypes.BlockID)
 	ph, err := PartSetHeaderFromProto(&bID.PartSetHeader)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBlockID, err)
 	}
 
 	blockID.PartSetHeader = *ph
 	blockID.Hash = bID.Hash
 
-	return blockID, blockID.ValidateBasic()
+	if err := blockID.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBlockID, err)
+	}
+	return blockID, nil
 }
 
-func CanonicalizeBlockID(bid protoTypes.BlockID) *protoTypes.CanonicalBlockID {
+// CanonicalizeBlockID returns the canonical form of bid that is signed, nil
+// for the zero BlockID of a nil vote.
+func CanonicalizeBlockID(bid protoTypes.BlockID) (*protoTypes.CanonicalBlockID, error) {
 	rbid, err := BlockIDFromProto(&bid)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	var cbid *protoTypes.CanonicalBlockID
 	if rbid == nil || rbid.IsZero() {
@@ -60,61 +64,83 @@ func CanonicalizeBlockID(bid protoTypes.BlockID) *protoTypes.CanonicalBlockID {
 		}
 	}
 
-	return cbid
+	return cbid, nil
 }
 
-func CanonicalizeVote(chainID string, vote *protoTypes.Vote) protoTypes.CanonicalVote {
+// CanonicalizeVote returns the canonical form of vote that is signed.
+func CanonicalizeVote(chainID string, vote *protoTypes.Vote) (protoTypes.CanonicalVote, error) {
+	if vote == nil {
+		return protoTypes.CanonicalVote{}, errors.New("nil vote")
+	}
+	blockID, err := CanonicalizeBlockID(vote.BlockID)
+	if err != nil {
+		return protoTypes.CanonicalVote{}, err
+	}
+
 	return protoTypes.CanonicalVote{
 		Type:      vote.Type,
 		Height:    vote.Height,       // encoded as sfixed64
 		Round:     int64(vote.Round), // encoded as sfixed64
-		BlockID:   CanonicalizeBlockID(vote.BlockID),
+		BlockID:   blockID,
 		Timestamp: vote.Timestamp,
 		ChainID:   chainID,
-	}
+	}, nil
 }
 
-func VoteSignBytes(chainID string, vote *protoTypes.Vote) []byte {
-	pb := CanonicalizeVote(chainID, vote)
+// VoteSignBytes returns the length-delimited canonical vote signed by the
+// validator.
+func VoteSignBytes(chainID string, vote *protoTypes.Vote) ([]byte, error) {
+	pb, err := CanonicalizeVote(chainID, vote)
+	if err != nil {
+		return nil, err
+	}
 	bz, err := protoio.MarshalDelimited(&pb)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("%w: %v", ErrBadEncoding, err)
 	}
 
-	return bz
+	return bz, nil
 }
 
-func Message() bool {
+// Message verifies a recorded Oraichain precommit at height 10320459. A
+// signature that does not verify is reported in the result, not as an error.
+func Message() (VoteResult, error) {
+	blockHash, err := hex.DecodeString("D89A2762A9996953D0396D56478A7A4C4F4ADA8C0631756FCC17E2DD0DD5BB08")
+	if err != nil {
+		return VoteResult{}, fmt.Errorf("%w: block hash: %v", ErrBadEncoding, err)
+	}
+	partsHash, err := hex.DecodeString("E987C5881C464D77416F0A52D811FB49F50E6BB592C2A63F921A0B679337A90E")
+	if err != nil {
+		return VoteResult{}, fmt.Errorf("%w: part set hash: %v", ErrBadEncoding, err)
+	}
+	timestamp, err := time.Parse(time.RFC3339, "2023-02-17T07:06:47.664674294Z")
+	if err != nil {
+		return VoteResult{}, fmt.Errorf("%w: timestamp: %v", ErrBadEncoding, err)
+	}
 
-	vote := protoTypes.CanonicalVote{
-		Type:   2,
+	vote := protoTypes.Vote{
+		Type:   protoTypes.PrecommitType,
 		Height: 10320459,
 		Round:  0,
-		BlockID: &protoTypes.CanonicalBlockID{
-			Hash: First(hex.DecodeString("D89A2762A9996953D0396D56478A7A4C4F4ADA8C0631756FCC17E2DD0DD5BB08")),
-			PartSetHeader: protoTypes.CanonicalPartSetHeader{
+		BlockID: protoTypes.BlockID{
+			Hash: blockHash,
+			PartSetHeader: protoTypes.PartSetHeader{
 				Total: 1,
-				Hash:  First(hex.DecodeString("E987C5881C464D77416F0A52D811FB49F50E6BB592C2A63F921A0B679337A90E")),
+				Hash:  partsHash,
 			},
 		},
-		Timestamp: First(time.Parse(time.RFC3339, "2023-02-17T07:06:47.664674294Z")),
-		ChainID:   "Oraichain",
+		Timestamp: timestamp,
 	}
 
-	// fmt.Println(vote,"\n")
-
-	bz, err := protoio.MarshalDelimited(&vote)
+	// // verify
+	publicKey, err := base64.StdEncoding.DecodeString("/ShOMJ4joYZBqPVFtD0+skU59lBh84uAyLkmeL6Dpwo=")
 	if err != nil {
-		panic(err)
+		return VoteResult{}, fmt.Errorf("%w: public key: %v", ErrBadEncoding, err)
+	}
+	signature, err := base64.StdEncoding.DecodeString("Oyfq86rjqsiZMPQUWTpKxYm9Ovu/od/XoQksOdq0jw+ITd38m6hcEtU7PpxZ51/DV4CMqJ3uWmyU4rPlKZ9RCQ==")
+	if err != nil {
+		return VoteResult{}, fmt.Errorf("%w: signature: %v", ErrBadEncoding, err)
 	}
 
-	// // verify
-	publicKey := ed25519.PublicKey(First(base64.StdEncoding.DecodeString("/ShOMJ4joYZBqPVFtD0+skU59lBh84uAyLkmeL6Dpwo=")))
-
-	signature := First(base64.StdEncoding.DecodeString("Oyfq86rjqsiZMPQUWTpKxYm9Ovu/od/XoQksOdq0jw+ITd38m6hcEtU7PpxZ51/DV4CMqJ3uWmyU4rPlKZ9RCQ=="))
-
-	result := ed25519.Verify(publicKey, bz, signature)
-
-	return result
-
+	return VerifyVote("Oraichain", &vote, ed25519.PubKey(publicKey), signature), nil
 }
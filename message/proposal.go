@@ -0,0 +1,148 @@
+package message
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mihongtech/tendermint/libs/protoio"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	protoTypes "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmTypes "github.com/tendermint/tendermint/types"
+)
+
+// CanonicalizeProposal returns the canonical form of proposal that is signed.
+func CanonicalizeProposal(chainID string, proposal *protoTypes.Proposal) (protoTypes.CanonicalProposal, error) {
+	if proposal == nil {
+		return protoTypes.CanonicalProposal{}, errors.New("nil proposal")
+	}
+	blockID, err := CanonicalizeBlockID(proposal.BlockID)
+	if err != nil {
+		return protoTypes.CanonicalProposal{}, err
+	}
+
+	return protoTypes.CanonicalProposal{
+		Type:      protoTypes.ProposalType,
+		Height:    proposal.Height,          // encoded as sfixed64
+		Round:     int64(proposal.Round),    // encoded as sfixed64
+		POLRound:  int64(proposal.PolRound), // -1 if there is no proof-of-lock round
+		BlockID:   blockID,
+		Timestamp: proposal.Timestamp,
+		ChainID:   chainID,
+	}, nil
+}
+
+// ProposalSignBytes returns the length-delimited canonical proposal signed by
+// the proposer.
+func ProposalSignBytes(chainID string, proposal *protoTypes.Proposal) ([]byte, error) {
+	pb, err := CanonicalizeProposal(chainID, proposal)
+	if err != nil {
+		return nil, err
+	}
+	bz, err := protoio.MarshalDelimited(&pb)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadEncoding, err)
+	}
+
+	return bz, nil
+}
+
+// ProposalResult is the detailed result of VerifyProposal.
+type ProposalResult struct {
+	Status           VoteStatus       `json:"status"`
+	Proposer         tmbytes.HexBytes `json:"proposer,omitempty"`
+	ExpectedProposer tmbytes.HexBytes `json:"expected_proposer,omitempty"`
+	SignBytes        []byte           `json:"sign_bytes,omitempty"`
+	Error            string           `json:"error,omitempty"`
+}
+
+// Valid reports whether the proposal signature was verified.
+func (r ProposalResult) Valid() bool {
+	return r.Status == VoteValid
+}
+
+// Err returns nil if the proposal was verified, or the typed error of its
+// status otherwise.
+func (r ProposalResult) Err() error {
+	return statusErr(r.Status, r.Error)
+}
+
+// maxProposerRound bounds the rounds ExpectedProposer derives the proposer
+// of, which takes one priority increment per round.
+const maxProposerRound = 1 << 16
+
+// ExpectedProposer returns the proposer of round of the height validatorSet
+// is for. validatorSet must carry the proposer of round 0, as sets built with
+// types.ValidatorSetFromExistingValidators from /validators do.
+func ExpectedProposer(validatorSet *tmTypes.ValidatorSet, round int32) (*tmTypes.Validator, error) {
+	if err := checkValidatorSet(validatorSet); err != nil {
+		return nil, err
+	}
+	if validatorSet.Proposer == nil {
+		return nil, fmt.Errorf("%w: no proposer", ErrInvalidValidatorSet)
+	}
+	if round < 0 || round > maxProposerRound {
+		return nil, fmt.Errorf("%w: round %d is not within [0, %d]", ErrInvalidRound, round, maxProposerRound)
+	}
+	if round == 0 {
+		return validatorSet.Proposer, nil
+	}
+	// each round without a decision moves the priorities on by one
+	return validatorSet.CopyIncrementProposerPriority(round).GetProposer(), nil
+}
+
+// VerifyProposal checks that proposal on chainID is signed by the validator
+// of validatorSet expected to propose at its round. If signature is empty,
+// proposal.Signature is used. A proposal that does not verify is reported in
+// the result; an error means the expected proposer could not be derived from
+// validatorSet and the proposal's round.
+func VerifyProposal(chainID string, proposal *protoTypes.Proposal, validatorSet *tmTypes.ValidatorSet, signature []byte) (ProposalResult, error) {
+	if proposal == nil {
+		return ProposalResult{Status: VoteMalformedBlockID, Error: "nil proposal"}, nil
+	}
+	if _, err := BlockIDFromProto(&proposal.BlockID); err != nil {
+		return ProposalResult{Status: VoteMalformedBlockID, Error: err.Error()}, nil
+	}
+	if proposal.PolRound < -1 || (proposal.PolRound >= 0 && proposal.PolRound >= proposal.Round) {
+		return ProposalResult{Status: ProposalInvalidPOLRound,
+			Error: fmt.Sprintf("POL round %d is not -1 or below round %d", proposal.PolRound, proposal.Round)}, nil
+	}
+
+	proposer, err := ExpectedProposer(validatorSet, proposal.Round)
+	if err != nil {
+		return ProposalResult{}, err
+	}
+	res := ProposalResult{ExpectedProposer: proposer.Address}
+	if err := checkKeyType(proposer.PubKey); err != nil {
+		res.Status = VoteUnsupportedKeyType
+		res.Error = err.Error()
+		return res, nil
+	}
+
+	if len(signature) == 0 {
+		signature = proposal.Signature
+	}
+	if res.SignBytes, err = ProposalSignBytes(chainID, proposal); err != nil {
+		res.Status = VoteMalformedBlockID
+		res.Error = err.Error()
+		return res, nil
+	}
+	if !proposer.PubKey.VerifySignature(res.SignBytes, signature) {
+		res.Status = VoteInvalidSignature
+		res.Error = "signature does not match the expected proposer"
+		// tell a signature of another validator apart from a bad one
+		for _, val := range validatorSet.Validators {
+			if checkKeyType(val.PubKey) == nil && val.PubKey.VerifySignature(res.SignBytes, signature) {
+				res.Status = ProposalWrongProposer
+				res.Proposer = val.Address
+				res.Error = fmt.Sprintf("proposal is signed by %v, the proposer of round %d is %v",
+					val.Address, proposal.Round, proposer.Address)
+				break
+			}
+		}
+		return res, nil
+	}
+
+	res.Status = VoteValid
+	res.Proposer = proposer.Address
+	return res, nil
+}
@@ -0,0 +1,98 @@
+package message
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmTypes "github.com/tendermint/tendermint/types"
+)
+
+// signedProposal returns a proposal of round with POL round polRound, signed
+// with key.
+func signedProposal(t *testing.T, key crypto.PrivKey, round, polRound int32) *tmproto.Proposal {
+	t.Helper()
+	proposal := &tmproto.Proposal{
+		Type:     tmproto.ProposalType,
+		Height:   10,
+		Round:    round,
+		PolRound: polRound,
+		BlockID: tmproto.BlockID{
+			Hash:          tmhash.Sum([]byte("block")),
+			PartSetHeader: tmproto.PartSetHeader{Total: 1, Hash: tmhash.Sum([]byte("parts"))},
+		},
+		Timestamp: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	signBytes, err := ProposalSignBytes(testChainID, proposal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proposal.Signature, err = key.Sign(signBytes); err != nil {
+		t.Fatal(err)
+	}
+	return proposal
+}
+
+// keyOf returns the key of val among keys, in the order of vals.
+func keyOf(vals *tmTypes.ValidatorSet, keys []crypto.PrivKey, val *tmTypes.Validator) crypto.PrivKey {
+	idx, _ := vals.GetByAddress(val.Address)
+	return keys[idx]
+}
+
+func TestVerifyProposal(t *testing.T) {
+	vals, keys := genValSet(4)
+	proposer := vals.GetProposer()
+	var other *tmTypes.Validator
+	for _, val := range vals.Validators {
+		if !bytes.Equal(val.Address, proposer.Address) {
+			other = val
+			break
+		}
+	}
+
+	tests := []struct {
+		name     string
+		proposal *tmproto.Proposal
+		want     VoteStatus
+	}{
+		{"valid", signedProposal(t, keyOf(vals, keys, proposer), 0, -1), VoteValid},
+		{"wrong proposer", signedProposal(t, keyOf(vals, keys, other), 0, -1), ProposalWrongProposer},
+		{"POL round not below round", signedProposal(t, keyOf(vals, keys, proposer), 0, 0), ProposalInvalidPOLRound},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := VerifyProposal(testChainID, tc.proposal, vals, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Status != tc.want {
+				t.Errorf("status %q (%s), want %q", res.Status, res.Error, tc.want)
+			}
+		})
+	}
+}
+
+func TestVerifyProposalInvalidInput(t *testing.T) {
+	vals, keys := genValSet(4)
+	proposal := signedProposal(t, keys[0], 0, -1)
+
+	noProposer := vals.Copy()
+	noProposer.Proposer = nil
+	if _, err := VerifyProposal(testChainID, proposal, noProposer, nil); !errors.Is(err, ErrInvalidValidatorSet) {
+		t.Errorf("set without proposer: got %v, want %v", err, ErrInvalidValidatorSet)
+	}
+	if _, err := VerifyProposal(testChainID, proposal, &tmTypes.ValidatorSet{}, nil); !errors.Is(err, ErrInvalidValidatorSet) {
+		t.Errorf("empty set: got %v, want %v", err, ErrInvalidValidatorSet)
+	}
+
+	for _, round := range []int32{-1, maxProposerRound + 1} {
+		proposal := signedProposal(t, keys[0], round, -1)
+		if _, err := VerifyProposal(testChainID, proposal, vals, nil); !errors.Is(err, ErrInvalidRound) {
+			t.Errorf("round %d: got %v, want %v", round, err, ErrInvalidRound)
+		}
+	}
+}
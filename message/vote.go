@@ -0,0 +1,126 @@
+package message
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tendermint/tendermint/crypto"
+	protoTypes "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmTypes "github.com/tendermint/tendermint/types"
+
+	"server/verifyValidator/validator"
+)
+
+// VoteStatus is the outcome of a vote or proposal signature check.
+type VoteStatus string
+
+const (
+	VoteValid              VoteStatus = "valid"
+	VoteInvalidSignature   VoteStatus = "invalid_signature"
+	VoteMalformedBlockID   VoteStatus = "malformed_block_id"
+	VoteUnsupportedKeyType VoteStatus = "unsupported_key_type"
+	// ProposalWrongProposer is a proposal from a validator that is not the
+	// proposer of its height and round.
+	ProposalWrongProposer VoteStatus = "wrong_proposer"
+	// ProposalInvalidPOLRound is a proposal whose proof-of-lock round is
+	// neither -1 nor below its round.
+	ProposalInvalidPOLRound VoteStatus = "invalid_pol_round"
+)
+
+// VoteResult is the detailed result of VerifyVote.
+type VoteResult struct {
+	Status    VoteStatus `json:"status"`
+	SignBytes []byte     `json:"sign_bytes,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// Valid reports whether the vote signature was verified.
+func (r VoteResult) Valid() bool {
+	return r.Status == VoteValid
+}
+
+// Err returns nil if the vote was verified, or the typed error of its status
+// otherwise.
+func (r VoteResult) Err() error {
+	return statusErr(r.Status, r.Error)
+}
+
+// statusErr wraps msg in the error of a vote or proposal status, unless msg
+// already comes from that error.
+func statusErr(status VoteStatus, msg string) error {
+	var err error
+	switch status {
+	case VoteValid:
+		return nil
+	case VoteInvalidSignature:
+		err = ErrSignatureMismatch
+	case VoteMalformedBlockID:
+		err = ErrInvalidBlockID
+	case VoteUnsupportedKeyType:
+		err = ErrUnsupportedKeyType
+	case ProposalWrongProposer:
+		err = ErrWrongProposer
+	case ProposalInvalidPOLRound:
+		err = ErrInvalidPOLRound
+	default:
+		return errors.New(msg)
+	}
+	switch {
+	case msg == "":
+		return err
+	case strings.HasPrefix(msg, err.Error()):
+		return fmt.Errorf("%w%s", err, strings.TrimPrefix(msg, err.Error()))
+	default:
+		return fmt.Errorf("%w: %s", err, msg)
+	}
+}
+
+// VerifyVote checks that signature is pubKey's signature over the canonical
+// sign bytes of vote on chainID. If signature is empty, vote.Signature is used.
+func VerifyVote(chainID string, vote *protoTypes.Vote, pubKey crypto.PubKey, signature []byte) VoteResult {
+	if vote == nil {
+		return VoteResult{Status: VoteMalformedBlockID, Error: "nil vote"}
+	}
+	if _, err := BlockIDFromProto(&vote.BlockID); err != nil {
+		return VoteResult{Status: VoteMalformedBlockID, Error: err.Error()}
+	}
+	if err := checkKeyType(pubKey); err != nil {
+		return VoteResult{Status: VoteUnsupportedKeyType, Error: err.Error()}
+	}
+
+	if len(signature) == 0 {
+		signature = vote.Signature
+	}
+	signBytes, err := VoteSignBytes(chainID, vote)
+	if err != nil {
+		return VoteResult{Status: VoteMalformedBlockID, Error: err.Error()}
+	}
+	if !pubKey.VerifySignature(signBytes, signature) {
+		return VoteResult{Status: VoteInvalidSignature, SignBytes: signBytes, Error: "signature does not match public key"}
+	}
+
+	return VoteResult{Status: VoteValid, SignBytes: signBytes}
+}
+
+// checkKeyType checks that pubKey is of a supported key type: ed25519,
+// secp256k1 or sr25519.
+func checkKeyType(pubKey crypto.PubKey) error {
+	return validator.CheckPubKey(pubKey)
+}
+
+// checkValidatorSet checks that validatorSet can be used without tendermint
+// panicking and that its addresses are those of its keys, see
+// validator.CheckSet.
+func checkValidatorSet(validatorSet *tmTypes.ValidatorSet) error {
+	if validatorSet == nil {
+		return fmt.Errorf("%w: nil validator set", ErrInvalidValidatorSet)
+	}
+	return validator.CheckSet(validatorSet.Validators)
+}
+
+// PubKeyFromBytes builds a public key of the given type from its raw bytes.
+// An empty keyType defaults to ed25519.
+func PubKeyFromBytes(keyType string, bz []byte) (crypto.PubKey, error) {
+	return validator.PubKeyFromBytes(keyType, bz)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sort"
+	"sync"
+
+	"goserver/light"
+	"goserver/message"
+	"goserver/rpc"
+
+	"server/headerTest/header"
+	"server/iavlTree/state"
+	"server/iavlTree/txdecode"
+	"server/iavlTree/txproof"
+	"server/verifyValidator/validator"
+)
+
+var (
+	// ErrUnsupportedService is returned when no handler is registered for
+	// the requested type_service.
+	ErrUnsupportedService = errors.New("unsupported service")
+	// ErrInternal is returned when a handler panics, so a bad request fails
+	// on its own instead of taking the worker down.
+	ErrInternal = errors.New("internal error")
+)
+
+// Handler verifies the decoded data payload of a request and returns a
+// result that is serialized into the result field of the Response.
+type Handler func(data json.RawMessage) (interface{}, error)
+
+type registry struct {
+	mtx      sync.RWMutex
+	handlers map[string]Handler
+}
+
+var services = &registry{handlers: make(map[string]Handler)}
+
+// Register adds a handler for the given type_service. It panics if a handler
+// is already registered under that name, since that is a programming error.
+func Register(typeService string, h Handler) {
+	services.mtx.Lock()
+	defer services.mtx.Unlock()
+
+	if h == nil {
+		panic(fmt.Sprintf("register %q: nil handler", typeService))
+	}
+	if _, ok := services.handlers[typeService]; ok {
+		panic(fmt.Sprintf("register %q: handler already registered", typeService))
+	}
+	services.handlers[typeService] = h
+}
+
+// RegisteredServices returns the sorted names of all registered services.
+func RegisteredServices() []string {
+	services.mtx.RLock()
+	defer services.mtx.RUnlock()
+
+	names := make([]string, 0, len(services.handlers))
+	for name := range services.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Dispatch runs the handler registered for typeService on data. A panic in
+// the handler is recovered and returned as ErrInternal.
+func Dispatch(typeService string, data json.RawMessage) (res interface{}, err error) {
+	services.mtx.RLock()
+	h, ok := services.handlers[typeService]
+	services.mtx.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedService, typeService)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("service %q panicked: %v\n%s", typeService, r, debug.Stack())
+			res, err = nil, fmt.Errorf("%w: %v", ErrInternal, r)
+		}
+	}()
+	return h(decodePayload(data))
+}
+
+// decodePayload unwraps a data field that was sent as a JSON-encoded string,
+// so handlers always receive the payload itself.
+func decodePayload(data json.RawMessage) json.RawMessage {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return json.RawMessage(s)
+	}
+	return data
+}
+
+// errorCode maps a handler error to a machine-readable code. Verify services
+// report failed checks in their result, so verification_failed is left for
+// services that build a proof from data that does not match its header.
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrUnsupportedService):
+		return "unsupported_service"
+	case errors.Is(err, ErrInternal):
+		return "internal_error"
+	case errors.Is(err, message.ErrInvalidBlockID):
+		return "invalid_block_id"
+	case errors.Is(err, message.ErrUnsupportedKeyType):
+		return "unsupported_key_type"
+	case errors.Is(err, message.ErrBadEncoding), errors.Is(err, rpc.ErrMalformedResponse),
+		errors.Is(err, state.ErrMalformedProof), errors.Is(err, txdecode.ErrMalformedTx),
+		isEncodingError(err):
+		return "bad_encoding"
+	case isVerificationFailure(err):
+		return "verification_failed"
+	default:
+		return "invalid_request"
+	}
+}
+
+// isVerificationFailure reports whether err is a well-formed request whose
+// signature, hash, proof or light client check failed.
+func isVerificationFailure(err error) bool {
+	for _, target := range []error{
+		message.ErrSignatureMismatch,
+		message.ErrHashMismatch,
+		validator.ErrAddressMismatch,
+		validator.ErrInvalidProof,
+		header.ErrInvalidProof,
+		header.ErrNotAdjacent,
+		txproof.ErrRootMismatch,
+		txproof.ErrInvalidProof,
+		state.ErrInvalidProof,
+		light.ErrInvalidHeader,
+		light.ErrNewValSetCantBeTrusted,
+		light.ErrOldHeaderExpired,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// isEncodingError reports whether err comes from decoding the JSON, base64 or
+// hex of a request.
+func isEncodingError(err error) bool {
+	var (
+		syntaxErr  *json.SyntaxError
+		typeErr    *json.UnmarshalTypeError
+		base64Err  base64.CorruptInputError
+		hexByteErr hex.InvalidByteError
+	)
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr) ||
+		errors.As(err, &base64Err) || errors.As(err, &hexByteErr) ||
+		errors.Is(err, hex.ErrLength)
+}
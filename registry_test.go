@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"goserver/rpc"
+
+	"server/iavlTree/txproof"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("%w: %q", ErrUnsupportedService, "x"), "unsupported_service"},
+		{fmt.Errorf("%w: boom", ErrInternal), "internal_error"},
+		{fmt.Errorf("parse request: %w", &json.SyntaxError{}), "bad_encoding"},
+		{fmt.Errorf("decode /commit: %w", rpc.ErrMalformedResponse), "bad_encoding"},
+		{fmt.Errorf("prove tx: %w", txproof.ErrRootMismatch), "verification_failed"},
+		{errors.New("missing proof"), "invalid_request"},
+	}
+	for _, tc := range tests {
+		if got := errorCode(tc.err); got != tc.want {
+			t.Errorf("errorCode(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestHandleRequestUnsupportedService(t *testing.T) {
+	res, _ := call(t, "no_such_service", struct{}{})
+	if res.Status != StatusError || res.ErrorCode != "unsupported_service" {
+		t.Fatalf("status %q, code %q, want error unsupported_service", res.Status, res.ErrorCode)
+	}
+}
+
+func TestHandleRequestRecordedVote(t *testing.T) {
+	res, result := call(t, "1", struct{}{})
+	if res.Status != StatusOK {
+		t.Fatalf("status %q: %s", res.Status, res.ErrorMessage)
+	}
+	if result["valid"] != true || result["status"] != "valid" {
+		t.Errorf("result %v, want a valid vote", result)
+	}
+}
+
+// TestHandleRequestFailedCheck checks that a check that ran but failed is
+// reported in the result, and a request that cannot be checked as an error.
+func TestHandleRequestFailedCheck(t *testing.T) {
+	sh, vals := genSignedHeader(t, 4)
+	_, otherVals := genSignedHeader(t, 4)
+
+	type rpcCommit struct {
+		Commit         json.RawMessage   `json:"commit"`
+		ValidatorPages []json.RawMessage `json:"validator_pages"`
+	}
+	tests := []struct {
+		name   string
+		req    rpcCommit
+		status string
+		valid  bool
+		code   string
+	}{
+		{
+			name:   "valid",
+			req:    rpcCommit{commitResponse(t, sh), []json.RawMessage{validatorsResponse(t, 10, vals)}},
+			status: StatusOK,
+			valid:  true,
+		},
+		{
+			name:   "validators of another header",
+			req:    rpcCommit{commitResponse(t, sh), []json.RawMessage{validatorsResponse(t, 10, otherVals)}},
+			status: StatusOK,
+		},
+		{
+			name:   "malformed commit",
+			req:    rpcCommit{json.RawMessage(`{"signed_header":{}}`), []json.RawMessage{validatorsResponse(t, 10, vals)}},
+			status: StatusError,
+			code:   "bad_encoding",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			res, result := call(t, "verify_rpc_commit", tc.req)
+			if res.Status != tc.status {
+				t.Fatalf("status %q (%s), want %q", res.Status, res.ErrorMessage, tc.status)
+			}
+			if res.ErrorCode != tc.code {
+				t.Errorf("error code %q, want %q", res.ErrorCode, tc.code)
+			}
+			if tc.status == StatusOK && result["valid"] != tc.valid {
+				t.Errorf("valid %v (%v), want %v", result["valid"], result["error"], tc.valid)
+			}
+		})
+	}
+}
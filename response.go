@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Version is the service version reported in every response. It is set at
+// build time with -ldflags "-X main.Version=<version>".
+var Version = "dev"
+
+// Response statuses.
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// Response is the versioned envelope every reply is published in. Result is
+// set when Status is ok, ErrorCode and ErrorMessage when it is error. A
+// verification that ran but failed, such as an invalid signature, is ok with
+// the failure in Result; error means the request could not be verified at
+// all, and ErrorCode tells a malformed request from an internal error.
+type Response struct {
+	Version      string      `json:"version"`
+	RequestID    string      `json:"request_id,omitempty"`
+	TypeService  string      `json:"type_service"`
+	Status       string      `json:"status"`
+	ErrorCode    string      `json:"error_code,omitempty"`
+	ErrorMessage string      `json:"error_message,omitempty"`
+	Result       interface{} `json:"result,omitempty"`
+	DurationMs   float64     `json:"duration_ms"`
+}
+
+// request is the envelope of an incoming message.
+type request struct {
+	RequestID   string          `json:"request_id,omitempty"`
+	TypeService string          `json:"type_service"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// handleRequest decodes body, dispatches it to its service and wraps the
+// outcome in a Response. requestID is used when the request carries none.
+func handleRequest(body []byte, requestID string) *Response {
+	start := time.Now()
+	res := &Response{Version: Version, RequestID: requestID}
+
+	var req request
+	err := json.Unmarshal(body, &req)
+	if err != nil {
+		err = fmt.Errorf("parse request: %w", err)
+	} else {
+		res.TypeService = req.TypeService
+		if req.RequestID != "" {
+			res.RequestID = req.RequestID
+		}
+		res.Result, err = Dispatch(req.TypeService, req.Data)
+	}
+
+	if err != nil {
+		res.Status = StatusError
+		res.ErrorCode = errorCode(err)
+		res.ErrorMessage = err.Error()
+		res.Result = nil
+	} else {
+		res.Status = StatusOK
+	}
+	res.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+	return res
+}
@@ -0,0 +1,306 @@
+// Package rpc decodes recorded tendermint RPC JSON responses into the types
+// the verifiers work on.
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	tmcrypto "github.com/tendermint/tendermint/proto/tendermint/crypto"
+	"github.com/tendermint/tendermint/types"
+
+	"server/verifyValidator/validator"
+)
+
+var (
+	// ErrMalformedResponse is a response that cannot be decoded or lacks
+	// the fields it must have.
+	ErrMalformedResponse = errors.New("malformed rpc response")
+	// ErrRPC is a JSON-RPC error response.
+	ErrRPC = errors.New("rpc error")
+	// ErrQueryFailed is an ABCI query that failed in the application.
+	ErrQueryFailed = errors.New("abci query failed")
+)
+
+// rpcError is the error object of a JSON-RPC response.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+// envelope is a JSON-RPC 2.0 response.
+type envelope struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result"`
+	Error   *rpcError       `json:"error"`
+}
+
+type blockResult struct {
+	BlockID types.BlockID `json:"block_id"`
+	Block   *types.Block  `json:"block"`
+}
+
+type headerResult struct {
+	Header *types.Header `json:"header"`
+}
+
+type commitResult struct {
+	SignedHeader types.SignedHeader `json:"signed_header"`
+	Canonical    bool               `json:"canonical"`
+}
+
+type deliverTxResult struct {
+	Code      uint32 `json:"code"`
+	Data      []byte `json:"data"`
+	GasWanted int64  `json:"gas_wanted"`
+	GasUsed   int64  `json:"gas_used"`
+}
+
+type blockResultsResult struct {
+	Height     int64             `json:"height"`
+	TxsResults []deliverTxResult `json:"txs_results"`
+}
+
+type validatorsResult struct {
+	BlockHeight int64              `json:"block_height"`
+	Validators  []*types.Validator `json:"validators"`
+	Count       int                `json:"count"`
+	Total       int                `json:"total"`
+}
+
+// unwrap returns the result of a JSON-RPC response, or bz itself if it is
+// already a bare result object.
+func unwrap(bz []byte) (json.RawMessage, error) {
+	var env envelope
+	if err := json.Unmarshal(bz, &env); err != nil {
+		return nil, fmt.Errorf("%w: decode rpc response: %v", ErrMalformedResponse, err)
+	}
+	if env.Error != nil {
+		return nil, fmt.Errorf("%w %d: %s %s", ErrRPC, env.Error.Code, env.Error.Message, env.Error.Data)
+	}
+	if env.JSONRPC == "" && env.Result == nil {
+		return bz, nil
+	}
+	if env.Result == nil {
+		return nil, fmt.Errorf("%w: no result", ErrMalformedResponse)
+	}
+	return env.Result, nil
+}
+
+func decode(bz []byte, v interface{}) error {
+	result, err := unwrap(bz)
+	if err != nil {
+		return err
+	}
+	if err := tmjson.Unmarshal(result, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+	}
+	return nil
+}
+
+// ParseBlock decodes a /block response.
+func ParseBlock(bz []byte) (types.BlockID, *types.Block, error) {
+	var res blockResult
+	if err := decode(bz, &res); err != nil {
+		return types.BlockID{}, nil, fmt.Errorf("decode /block: %w", err)
+	}
+	if res.Block == nil {
+		return types.BlockID{}, nil, fmt.Errorf("%w: decode /block: missing block", ErrMalformedResponse)
+	}
+	return res.BlockID, res.Block, nil
+}
+
+// ParseTxs decodes the transactions of a /block response.
+func ParseTxs(bz []byte) (types.Txs, error) {
+	_, block, err := ParseBlock(bz)
+	if err != nil {
+		return nil, err
+	}
+	return block.Data.Txs, nil
+}
+
+// ParseBlockResults decodes a /block_results response. Only the
+// deterministic fields of each result are kept, since events and logs are not
+// committed to by the LastResultsHash.
+func ParseBlockResults(bz []byte) (int64, []*abci.ResponseDeliverTx, error) {
+	var res blockResultsResult
+	if err := decode(bz, &res); err != nil {
+		return 0, nil, fmt.Errorf("decode /block_results: %w", err)
+	}
+
+	results := make([]*abci.ResponseDeliverTx, len(res.TxsResults))
+	for i, r := range res.TxsResults {
+		results[i] = &abci.ResponseDeliverTx{
+			Code:      r.Code,
+			Data:      r.Data,
+			GasWanted: r.GasWanted,
+			GasUsed:   r.GasUsed,
+		}
+	}
+	return res.Height, results, nil
+}
+
+// ParseHeader decodes the header of a /header, /block or /commit response.
+func ParseHeader(bz []byte) (*types.Header, error) {
+	result, err := unwrap(bz)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(result, &fields); err != nil {
+		return nil, fmt.Errorf("%w: decode header: %v", ErrMalformedResponse, err)
+	}
+
+	switch {
+	case fields["header"] != nil:
+		var res headerResult
+		if err := tmjson.Unmarshal(result, &res); err != nil {
+			return nil, fmt.Errorf("%w: decode /header: %v", ErrMalformedResponse, err)
+		}
+		if res.Header == nil {
+			return nil, fmt.Errorf("%w: decode /header: null header", ErrMalformedResponse)
+		}
+		return res.Header, nil
+	case fields["block"] != nil:
+		_, block, err := ParseBlock(result)
+		if err != nil {
+			return nil, err
+		}
+		return &block.Header, nil
+	case fields["signed_header"] != nil:
+		sh, err := ParseCommit(result)
+		if err != nil {
+			return nil, err
+		}
+		return sh.Header, nil
+	default:
+		return nil, fmt.Errorf("%w: decode header: no header, block or signed_header", ErrMalformedResponse)
+	}
+}
+
+// ParseCommit decodes a /commit response into the signed header.
+func ParseCommit(bz []byte) (*types.SignedHeader, error) {
+	var res commitResult
+	if err := decode(bz, &res); err != nil {
+		return nil, fmt.Errorf("decode /commit: %w", err)
+	}
+	if res.SignedHeader.Header == nil || res.SignedHeader.Commit == nil {
+		return nil, fmt.Errorf("%w: decode /commit: incomplete signed header", ErrMalformedResponse)
+	}
+	return &res.SignedHeader, nil
+}
+
+// ParseValidators decodes one page of a /validators response.
+func ParseValidators(bz []byte) (height int64, vals []*types.Validator, total int, err error) {
+	var res validatorsResult
+	if err := decode(bz, &res); err != nil {
+		return 0, nil, 0, fmt.Errorf("decode /validators: %w", err)
+	}
+	return res.BlockHeight, res.Validators, res.Total, nil
+}
+
+// ParseValidatorSet decodes and joins the pages of a /validators response,
+// in order, into the full validator set at that height.
+func ParseValidatorSet(pages ...[]byte) (*types.ValidatorSet, error) {
+	var (
+		vals   []*types.Validator
+		height int64
+		total  int
+	)
+	for i, page := range pages {
+		h, pageVals, t, err := ParseValidators(page)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", i+1, err)
+		}
+		if i > 0 && (h != height || t != total) {
+			return nil, fmt.Errorf("%w: page %d is for height %d of %d validators, expected height %d of %d",
+				ErrMalformedResponse, i+1, h, t, height, total)
+		}
+		height, total = h, t
+		vals = append(vals, pageVals...)
+	}
+
+	if total != 0 && len(vals) != total {
+		return nil, fmt.Errorf("%w: got %d of %d validators, pass every page", ErrMalformedResponse, len(vals), total)
+	}
+	// tendermint panics on a nil validator or too much voting power
+	if err := validator.CheckSet(vals); err != nil {
+		return nil, err
+	}
+	// the priorities are those after the proposer of this height was
+	// picked, which tendermint recovers the proposer from
+	return types.ValidatorSetFromExistingValidators(vals)
+}
+
+// ParseLightBlock decodes a /commit response and the pages of the /validators
+// response at the same height into a light block. It does not check that the
+// validator set matches the header.
+func ParseLightBlock(commit []byte, validatorPages ...[]byte) (*types.LightBlock, error) {
+	sh, err := ParseCommit(commit)
+	if err != nil {
+		return nil, err
+	}
+	vals, err := ParseValidatorSet(validatorPages...)
+	if err != nil {
+		return nil, fmt.Errorf("decode validators of height %d: %w", sh.Height, err)
+	}
+	return &types.LightBlock{SignedHeader: sh, ValidatorSet: vals}, nil
+}
+
+// ABCIQuery is the response of an /abci_query call.
+type ABCIQuery struct {
+	Code      uint32             `json:"code"`
+	Log       string             `json:"log"`
+	Key       []byte             `json:"key"`
+	Value     []byte             `json:"value"`
+	ProofOps  *tmcrypto.ProofOps `json:"proofOps"`
+	Height    int64              `json:"height"`
+	Codespace string             `json:"codespace"`
+}
+
+type abciQueryResult struct {
+	Response struct {
+		Code     uint32             `json:"code"`
+		Log      string             `json:"log"`
+		Key      []byte             `json:"key"`
+		Value    []byte             `json:"value"`
+		ProofOps *tmcrypto.ProofOps `json:"proofOps"`
+		// proof ops are named proof_ops by some node versions
+		ProofOpsSnake *tmcrypto.ProofOps `json:"proof_ops"`
+		Height        int64              `json:"height"`
+		Codespace     string             `json:"codespace"`
+	} `json:"response"`
+}
+
+// ParseABCIQuery decodes an /abci_query response. A query that failed in the
+// application is returned as an error.
+func ParseABCIQuery(bz []byte) (*ABCIQuery, error) {
+	var res abciQueryResult
+	if err := decode(bz, &res); err != nil {
+		return nil, fmt.Errorf("decode /abci_query: %w", err)
+	}
+
+	resp := res.Response
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("%w with code %d (%s): %s", ErrQueryFailed, resp.Code, resp.Codespace, resp.Log)
+	}
+
+	query := &ABCIQuery{
+		Code:      resp.Code,
+		Log:       resp.Log,
+		Key:       resp.Key,
+		Value:     resp.Value,
+		ProofOps:  resp.ProofOps,
+		Height:    resp.Height,
+		Codespace: resp.Codespace,
+	}
+	if query.ProofOps == nil {
+		query.ProofOps = resp.ProofOpsSnake
+	}
+	return query, nil
+}
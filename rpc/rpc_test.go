@@ -0,0 +1,113 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	"github.com/tendermint/tendermint/types"
+)
+
+// response returns result as the JSON-RPC response to a call.
+func response(t *testing.T, result interface{}) []byte {
+	t.Helper()
+	bz, err := tmjson.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":-1,"result":%s}`, bz))
+}
+
+func TestParseHeader(t *testing.T) {
+	h := &types.Header{ChainID: "test-chain", Height: 10}
+	commit := &types.Commit{Height: 10}
+	tests := []struct {
+		name string
+		bz   []byte
+	}{
+		{"/header", response(t, headerResult{Header: h})},
+		{"/commit", response(t, commitResult{SignedHeader: types.SignedHeader{Header: h, Commit: commit}})},
+		{"/block", response(t, blockResult{Block: &types.Block{Header: *h}})},
+		{"bare result", []byte(`{"header":{"chain_id":"test-chain","height":"10"}}`)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseHeader(tc.bz)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ChainID != h.ChainID || got.Height != h.Height {
+				t.Fatalf("got header %d of %q, want %d of %q", got.Height, got.ChainID, h.Height, h.ChainID)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func([]byte) error
+		bz    string
+		want  error
+	}{
+		{"not JSON", parseHeader, `{`, ErrMalformedResponse},
+		{"rpc error", parseHeader, `{"jsonrpc":"2.0","error":{"code":-32603,"message":"Internal error"}}`, ErrRPC},
+		{"no result", parseHeader, `{"jsonrpc":"2.0"}`, ErrMalformedResponse},
+		{"null header", parseHeader, `{"header":null}`, ErrMalformedResponse},
+		{"no header", parseHeader, `{"height":"10"}`, ErrMalformedResponse},
+		{"incomplete signed header", parseCommit, `{"signed_header":{"header":{"height":"10"}}}`, ErrMalformedResponse},
+		{"null block", parseBlock, `{"block_id":{},"block":null}`, ErrMalformedResponse},
+		{"missing validator page", parseValidatorSet, `{"block_height":"10","validators":[],"count":"0","total":"2"}`, ErrMalformedResponse},
+		{"failed query", parseABCIQuery, `{"response":{"code":18,"codespace":"sdk","log":"invalid request"}}`, ErrQueryFailed},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.parse([]byte(tc.bz)); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func parseHeader(bz []byte) error {
+	_, err := ParseHeader(bz)
+	return err
+}
+
+func parseCommit(bz []byte) error {
+	_, err := ParseCommit(bz)
+	return err
+}
+
+func parseBlock(bz []byte) error {
+	_, _, err := ParseBlock(bz)
+	return err
+}
+
+func parseValidatorSet(bz []byte) error {
+	_, err := ParseValidatorSet(bz)
+	return err
+}
+
+func parseABCIQuery(bz []byte) error {
+	_, err := ParseABCIQuery(bz)
+	return err
+}
+
+func TestParseABCIQueryProofOps(t *testing.T) {
+	// some node versions name the proof ops proof_ops
+	for _, name := range []string{"proofOps", "proof_ops"} {
+		bz := fmt.Sprintf(`{"response":{"value":"AQI=","%s":{"ops":[{"type":"ics23:iavl","key":"a2V5","data":""}]},"height":"10"}}`, name)
+		query, err := ParseABCIQuery([]byte(bz))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if query.ProofOps == nil || len(query.ProofOps.Ops) != 1 || string(query.ProofOps.Ops[0].Key) != "key" {
+			t.Errorf("%s: proof ops %v", name, query.ProofOps)
+		}
+		if query.Height != 10 {
+			t.Errorf("%s: height %d, want 10", name, query.Height)
+		}
+	}
+}
@@ -0,0 +1,408 @@
+package store
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	"github.com/tendermint/tendermint/types"
+)
+
+// Record operations of the log.
+const (
+	opPut    byte = 1
+	opDelete byte = 2
+)
+
+const (
+	// recordHeaderSize is the prefix of each record: the payload length, the
+	// CRC32 of the payload and the CRC32 of those first 8 bytes, so a
+	// damaged length is told apart from a record cut short by a crash.
+	recordHeaderSize = 12
+	// maxRecordSize bounds a record payload, far above the size of a light
+	// block with a full validator set.
+	maxRecordSize = 64 << 20
+)
+
+// FileStore is a Store kept in a single append-only file. Every save or
+// delete appends a checksummed record; the file is read into memory when it is
+// opened and rewritten without the stale records when it is pruned. A torn
+// last record, left by a crash, is discarded; any other damage fails the open
+// with ErrCorrupt.
+type FileStore struct {
+	mtx     sync.RWMutex
+	path    string
+	file    *os.File
+	chains  map[string]map[int64]entry
+	garbage int
+}
+
+type entry struct {
+	time  time.Time
+	value []byte
+}
+
+// storedBlock is the encoding of a light block. tmjson does not handle the
+// embedded SignedHeader of types.LightBlock.
+type storedBlock struct {
+	SignedHeader *types.SignedHeader `json:"signed_header"`
+	ValidatorSet *types.ValidatorSet `json:"validator_set"`
+}
+
+// OpenFileStore opens the store at path, creating the file if needed.
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{
+		path:   path,
+		chains: make(map[string]map[int64]entry),
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	valid, err := s.load(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("load %s: %w", path, err)
+	}
+	if err := f.Truncate(valid); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(valid, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	s.file = f
+
+	if s.garbage > 0 {
+		if err := s.compact(); err != nil {
+			s.file.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// load replays the records of f and returns the length of its valid prefix,
+// which excludes a torn last record: a partial header, or a valid header whose
+// payload runs past the end of the file or fails its checksum.
+func (s *FileStore) load(f *os.File) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+
+	r := bufio.NewReader(f)
+	var (
+		offset int64
+		head   [recordHeaderSize]byte
+	)
+	for {
+		if _, err := io.ReadFull(r, head[:]); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return offset, nil
+			}
+			return 0, err
+		}
+		if crc32.ChecksumIEEE(head[:8]) != binary.BigEndian.Uint32(head[8:]) {
+			return 0, fmt.Errorf("%w: header checksum mismatch in record at offset %d", ErrCorrupt, offset)
+		}
+		n := int64(binary.BigEndian.Uint32(head[:4]))
+		if n > maxRecordSize {
+			return 0, fmt.Errorf("%w: record at offset %d is %d bytes, more than %d",
+				ErrCorrupt, offset, n, maxRecordSize)
+		}
+		end := offset + recordHeaderSize + n
+		if end > size {
+			// the header is intact, so only a torn write of the last
+			// record ends past the file
+			return offset, nil
+		}
+		payload := make([]byte, n)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return 0, err
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(head[4:8]) {
+			if end == size {
+				return offset, nil
+			}
+			return 0, fmt.Errorf("%w: checksum mismatch in record at offset %d", ErrCorrupt, offset)
+		}
+		if err := s.apply(payload); err != nil {
+			return 0, fmt.Errorf("%w: record at offset %d: %v", ErrCorrupt, offset, err)
+		}
+		offset = end
+	}
+}
+
+// apply updates the in-memory index with a record payload:
+// op | uvarint len(chainID) | chainID | varint height | varint unix nanos | value.
+func (s *FileStore) apply(payload []byte) error {
+	if len(payload) == 0 {
+		return errors.New("empty record")
+	}
+	op, rest := payload[0], payload[1:]
+
+	n, k := binary.Uvarint(rest)
+	if k <= 0 || uint64(len(rest)-k) < n {
+		return errors.New("bad chain ID")
+	}
+	chainID, rest := string(rest[k:k+int(n)]), rest[k+int(n):]
+	height, k := binary.Varint(rest)
+	if k <= 0 {
+		return errors.New("bad height")
+	}
+	rest = rest[k:]
+
+	blocks := s.chains[chainID]
+	_, exists := blocks[height]
+
+	switch op {
+	case opPut:
+		nanos, k := binary.Varint(rest)
+		if k <= 0 {
+			return errors.New("bad time")
+		}
+		if blocks == nil {
+			blocks = make(map[int64]entry)
+			s.chains[chainID] = blocks
+		}
+		blocks[height] = entry{time: time.Unix(0, nanos).UTC(), value: rest[k:]}
+		if exists {
+			s.garbage++
+		}
+	case opDelete:
+		delete(blocks, height)
+		// the delete record and the put it cancels
+		s.garbage += 2
+	default:
+		return fmt.Errorf("unknown operation %d", op)
+	}
+	return nil
+}
+
+func encodeRecord(op byte, chainID string, height int64, t time.Time, value []byte) []byte {
+	payload := make([]byte, 0, 1+3*binary.MaxVarintLen64+len(chainID)+len(value))
+	payload = append(payload, op)
+	payload = binary.AppendUvarint(payload, uint64(len(chainID)))
+	payload = append(payload, chainID...)
+	payload = binary.AppendVarint(payload, height)
+	if op == opPut {
+		payload = binary.AppendVarint(payload, t.UnixNano())
+		payload = append(payload, value...)
+	}
+
+	record := make([]byte, recordHeaderSize, recordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(record[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(payload))
+	binary.BigEndian.PutUint32(record[8:], crc32.ChecksumIEEE(record[:8]))
+	return append(record, payload...)
+}
+
+// write appends records to the file and syncs it.
+func (s *FileStore) write(records ...[]byte) error {
+	if s.file == nil {
+		return errors.New("store is closed")
+	}
+	for _, record := range records {
+		if _, err := s.file.Write(record); err != nil {
+			return err
+		}
+	}
+	return s.file.Sync()
+}
+
+// compact rewrites the file with only the live records.
+func (s *FileStore) compact() error {
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for chainID, blocks := range s.chains {
+		for height, e := range blocks {
+			if _, err := w.Write(encodeRecord(opPut, chainID, height, e.time, e.value)); err != nil {
+				f.Close()
+				return err
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		f.Close()
+		return err
+	}
+
+	s.file.Close()
+	s.file = f
+	s.garbage = 0
+	return nil
+}
+
+// Save implements Store.
+func (s *FileStore) Save(lb *types.LightBlock) error {
+	if lb == nil || lb.SignedHeader == nil || lb.Header == nil {
+		return errors.New("incomplete light block")
+	}
+	value, err := tmjson.Marshal(storedBlock{SignedHeader: lb.SignedHeader, ValidatorSet: lb.ValidatorSet})
+	if err != nil {
+		return fmt.Errorf("encode light block %d: %w", lb.Height, err)
+	}
+
+	if len(value) > maxRecordSize-len(lb.ChainID)-1-3*binary.MaxVarintLen64 {
+		return fmt.Errorf("light block %d is too large to store: %d bytes", lb.Height, len(value))
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	record := encodeRecord(opPut, lb.ChainID, lb.Height, lb.Time, value)
+	if err := s.write(record); err != nil {
+		return err
+	}
+	return s.apply(record[recordHeaderSize:])
+}
+
+// LightBlock implements Store.
+func (s *FileStore) LightBlock(chainID string, height int64) (*types.LightBlock, error) {
+	s.mtx.RLock()
+	e, ok := s.chains[chainID][height]
+	s.mtx.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s at height %d", ErrNotFound, chainID, height)
+	}
+	return decodeBlock(e.value)
+}
+
+// Latest implements Store.
+func (s *FileStore) Latest(chainID string) (*types.LightBlock, error) {
+	s.mtx.RLock()
+	var (
+		latest int64
+		value  []byte
+	)
+	for height, e := range s.chains[chainID] {
+		if value == nil || height > latest {
+			latest, value = height, e.value
+		}
+	}
+	s.mtx.RUnlock()
+
+	if value == nil {
+		return nil, fmt.Errorf("%w: no trusted light block for %s", ErrNotFound, chainID)
+	}
+	return decodeBlock(value)
+}
+
+// Range implements Store.
+func (s *FileStore) Range(chainID string, from, to int64) ([]*types.LightBlock, error) {
+	s.mtx.RLock()
+	var heights []int64
+	values := make(map[int64][]byte)
+	for height, e := range s.chains[chainID] {
+		if height >= from && (to == 0 || height <= to) {
+			heights = append(heights, height)
+			values[height] = e.value
+		}
+	}
+	s.mtx.RUnlock()
+
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	blocks := make([]*types.LightBlock, len(heights))
+	for i, height := range heights {
+		lb, err := decodeBlock(values[height])
+		if err != nil {
+			return nil, err
+		}
+		blocks[i] = lb
+	}
+	return blocks, nil
+}
+
+// PruneBelow implements Store.
+func (s *FileStore) PruneBelow(chainID string, height int64) (int, error) {
+	return s.prune(chainID, func(h int64, _ entry, _ int64) bool { return h < height })
+}
+
+// PruneBefore implements Store.
+func (s *FileStore) PruneBefore(chainID string, t time.Time) (int, error) {
+	return s.prune(chainID, func(h int64, e entry, latest int64) bool {
+		return h != latest && e.time.Before(t)
+	})
+}
+
+func (s *FileStore) prune(chainID string, drop func(height int64, e entry, latest int64) bool) (int, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	blocks := s.chains[chainID]
+	var latest int64
+	for height := range blocks {
+		if height > latest {
+			latest = height
+		}
+	}
+
+	var records [][]byte
+	for height, e := range blocks {
+		if drop(height, e, latest) {
+			records = append(records, encodeRecord(opDelete, chainID, height, time.Time{}, nil))
+		}
+	}
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	if err := s.write(records...); err != nil {
+		return 0, err
+	}
+	for _, record := range records {
+		if err := s.apply(record[recordHeaderSize:]); err != nil {
+			return 0, err
+		}
+	}
+	if len(blocks) == 0 {
+		delete(s.chains, chainID)
+	}
+	return len(records), s.compact()
+}
+
+// Close implements Store.
+func (s *FileStore) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+func decodeBlock(value []byte) (*types.LightBlock, error) {
+	var b storedBlock
+	if err := tmjson.Unmarshal(value, &b); err != nil {
+		return nil, fmt.Errorf("decode light block: %w", err)
+	}
+	return &types.LightBlock{SignedHeader: b.SignedHeader, ValidatorSet: b.ValidatorSet}, nil
+}
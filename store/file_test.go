@@ -0,0 +1,195 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+const testChainID = "test-chain"
+
+// testBlock returns a light block of height with just enough of a header to
+// be stored.
+func testBlock(height int64) *types.LightBlock {
+	return &types.LightBlock{
+		SignedHeader: &types.SignedHeader{
+			Header: &types.Header{
+				ChainID: testChainID,
+				Height:  height,
+				Time:    time.Date(2026, 1, 1, 0, int(height), 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+// writeStore saves heights 1 to n in a new store file and returns its path
+// and the offset of each record.
+func writeStore(t *testing.T, n int) (string, []int64) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "trusted.db")
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offsets := make([]int64, n)
+	for i := range offsets {
+		offsets[i] = fileSize(t, path)
+		if err := s.Save(testBlock(int64(i + 1))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path, offsets
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Size()
+}
+
+// flipByte inverts the byte of the file at offset.
+func flipByte(t *testing.T, path string, offset int64) {
+	t.Helper()
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bz[offset] ^= 0xff
+	if err := os.WriteFile(path, bz, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkHeights checks that s holds exactly heights 1 to n.
+func checkHeights(t *testing.T, s Store, n int64) {
+	t.Helper()
+	blocks, err := s.Range(testChainID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(blocks)) != n {
+		t.Fatalf("store holds %d light blocks, want %d", len(blocks), n)
+	}
+	for i, lb := range blocks {
+		if lb.Height != int64(i+1) {
+			t.Fatalf("light block #%d at height %d, want %d", i, lb.Height, i+1)
+		}
+	}
+}
+
+func TestFileStoreReopenAfterAppend(t *testing.T) {
+	path, _ := writeStore(t, 2)
+
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(testBlock(3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	checkHeights(t, s, 3)
+	lb, err := s.Latest(testChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lb.Height != 3 {
+		t.Errorf("latest height %d, want 3", lb.Height)
+	}
+}
+
+func TestFileStoreTornTail(t *testing.T) {
+	for name, cut := range map[string]int64{
+		"partial header":  recordHeaderSize / 2,
+		"partial payload": recordHeaderSize + 1,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path, offsets := writeStore(t, 3)
+			if err := os.Truncate(path, offsets[2]+cut); err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := OpenFileStore(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkHeights(t, s, 2)
+			if size := fileSize(t, path); size != offsets[2] {
+				t.Errorf("file truncated to %d bytes, want %d", size, offsets[2])
+			}
+
+			// records appended after the torn one are read back
+			if err := s.Save(testBlock(3)); err != nil {
+				t.Fatal(err)
+			}
+			s.Close()
+			s, err = OpenFileStore(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+			checkHeights(t, s, 3)
+		})
+	}
+}
+
+func TestFileStoreCorruptLength(t *testing.T) {
+	for name, record := range map[string]int{"first": 0, "last": 2} {
+		t.Run(name, func(t *testing.T) {
+			path, offsets := writeStore(t, 3)
+			size := fileSize(t, path)
+			// the top byte of the length
+			flipByte(t, path, offsets[record])
+
+			if _, err := OpenFileStore(path); !errors.Is(err, ErrCorrupt) {
+				t.Fatalf("got %v, want %v", err, ErrCorrupt)
+			}
+			if got := fileSize(t, path); got != size {
+				t.Errorf("file is %d bytes after a failed open, want %d", got, size)
+			}
+		})
+	}
+}
+
+func TestFileStoreChecksumMismatch(t *testing.T) {
+	path, offsets := writeStore(t, 3)
+	size := fileSize(t, path)
+	flipByte(t, path, offsets[1]+recordHeaderSize)
+
+	if _, err := OpenFileStore(path); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("got %v, want %v", err, ErrCorrupt)
+	}
+	if got := fileSize(t, path); got != size {
+		t.Errorf("file is %d bytes after a failed open, want %d", got, size)
+	}
+}
+
+func TestFileStoreChecksumMismatchLastRecord(t *testing.T) {
+	path, offsets := writeStore(t, 3)
+	flipByte(t, path, offsets[2]+recordHeaderSize)
+
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	checkHeights(t, s, 2)
+}
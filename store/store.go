@@ -0,0 +1,41 @@
+// Package store persists verified light blocks, so that verification requests
+// can anchor to state that was already trusted, across restarts.
+package store
+
+import (
+	"errors"
+	"time"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// ErrNotFound is returned when no light block is stored for a chain and
+// height.
+var ErrNotFound = errors.New("light block not found")
+
+// ErrCorrupt is returned when opening a store whose file is damaged other
+// than by a record torn at its end.
+var ErrCorrupt = errors.New("store file is corrupt")
+
+// Store keeps verified light blocks keyed by chain ID and height.
+type Store interface {
+	// Save stores lb, replacing any light block at the same chain and
+	// height.
+	Save(lb *types.LightBlock) error
+	// LightBlock returns the light block at height.
+	LightBlock(chainID string, height int64) (*types.LightBlock, error)
+	// Latest returns the highest trusted light block of the chain.
+	Latest(chainID string) (*types.LightBlock, error)
+	// Range returns the light blocks with heights in [from, to], sorted by
+	// height. A to of 0 means no upper bound.
+	Range(chainID string, from, to int64) ([]*types.LightBlock, error)
+	// PruneBelow deletes the light blocks below height and returns how
+	// many were deleted.
+	PruneBelow(chainID string, height int64) (int, error)
+	// PruneBefore deletes the light blocks whose header time is before t
+	// and returns how many were deleted. The latest light block is always
+	// kept.
+	PruneBefore(chainID string, t time.Time) (int, error)
+	// Close releases the store.
+	Close() error
+}
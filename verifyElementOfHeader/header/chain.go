@@ -0,0 +1,55 @@
+package header
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// VerifyAdjacentHeaders checks that next directly follows prev: next is at the
+// following height on the same chain, is later in time, commits to prev's hash
+// in its LastBlockID and is signed by the validator set prev announced as
+// NextValidatorsHash.
+func VerifyAdjacentHeaders(prev, next *types.Header) error {
+	if prev == nil || next == nil {
+		return fmt.Errorf("%w: nil header", ErrInvalidHeader)
+	}
+	if next.ChainID != prev.ChainID {
+		return fmt.Errorf("%w: header %d is on chain %q, header %d is on chain %q",
+			ErrNotAdjacent, next.Height, next.ChainID, prev.Height, prev.ChainID)
+	}
+	if next.Height != prev.Height+1 {
+		return fmt.Errorf("%w: header %d does not follow header %d", ErrNotAdjacent, next.Height, prev.Height)
+	}
+	if !next.Time.After(prev.Time) {
+		return fmt.Errorf("%w: header %d time %v is not after header %d time %v",
+			ErrNotAdjacent, next.Height, next.Time, prev.Height, prev.Time)
+	}
+
+	prevHash := prev.Hash()
+	if prevHash == nil {
+		return fmt.Errorf("%w: header %d cannot be hashed", ErrInvalidHeader, prev.Height)
+	}
+	if !bytes.Equal(next.LastBlockID.Hash, prevHash) {
+		return fmt.Errorf("%w: header %d last block hash %v does not match header %d hash %v",
+			ErrNotAdjacent, next.Height, next.LastBlockID.Hash, prev.Height, prevHash)
+	}
+	if !bytes.Equal(next.ValidatorsHash, prev.NextValidatorsHash) {
+		return fmt.Errorf("%w: header %d validators hash %v does not match header %d next validators hash %v",
+			ErrNotAdjacent, next.Height, next.ValidatorsHash, prev.Height, prev.NextValidatorsHash)
+	}
+	return nil
+}
+
+// VerifyHeaderChain checks that headers, sorted by height, form a single
+// chain. It returns the index of the first header that does not follow its
+// predecessor along with the error, or -1 and nil.
+func VerifyHeaderChain(headers []*types.Header) (int, error) {
+	for i := 1; i < len(headers); i++ {
+		if err := VerifyAdjacentHeaders(headers[i-1], headers[i]); err != nil {
+			return i, err
+		}
+	}
+	return -1, nil
+}
@@ -0,0 +1,19 @@
+package header
+
+import "errors"
+
+// Errors returned, wrapped, by this package, so callers can classify them
+// with errors.Is.
+var (
+	// ErrInvalidHeader is a header that is nil or cannot be hashed.
+	ErrInvalidHeader = errors.New("invalid header")
+	// ErrUnknownField is a header field name or index that does not exist.
+	ErrUnknownField = errors.New("unknown header field")
+	// ErrInvalidFieldValue is a field value of the wrong type.
+	ErrInvalidFieldValue = errors.New("invalid header field value")
+	// ErrInvalidProof is a header field proof that does not verify.
+	ErrInvalidProof = errors.New("invalid header field proof")
+	// ErrNotAdjacent is a header that does not directly follow the one
+	// before it.
+	ErrNotAdjacent = errors.New("headers are not adjacent")
+)
@@ -0,0 +1,277 @@
+package header
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	gogotypes "github.com/gogo/protobuf/types"
+
+	"github.com/tendermint/tendermint/crypto/merkle"
+	"github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
+)
+
+// HeaderField identifies a field of types.Header by its leaf index in the
+// Merkle tree whose root is the block hash.
+type HeaderField int
+
+const (
+	FieldVersion HeaderField = iota
+	FieldChainID
+	FieldHeight
+	FieldTime
+	FieldLastBlockID
+	FieldLastCommitHash
+	FieldDataHash
+	FieldValidatorsHash
+	FieldNextValidatorsHash
+	FieldConsensusHash
+	FieldAppHash
+	FieldLastResultsHash
+	FieldEvidenceHash
+	FieldProposerAddress
+
+	// NumHeaderFields is the number of leaves in the header Merkle tree.
+	NumHeaderFields
+)
+
+var fieldNames = [NumHeaderFields]string{
+	"version",
+	"chain_id",
+	"height",
+	"time",
+	"last_block_id",
+	"last_commit_hash",
+	"data_hash",
+	"validators_hash",
+	"next_validators_hash",
+	"consensus_hash",
+	"app_hash",
+	"last_results_hash",
+	"evidence_hash",
+	"proposer_address",
+}
+
+// String returns the tendermint JSON name of the field.
+func (f HeaderField) String() string {
+	if !f.Valid() {
+		return fmt.Sprintf("HeaderField(%d)", int(f))
+	}
+	return fieldNames[f]
+}
+
+// Valid reports whether f is a known header field.
+func (f HeaderField) Valid() bool {
+	return f >= 0 && f < NumHeaderFields
+}
+
+// ParseHeaderField returns the field with the given tendermint JSON name,
+// e.g. "app_hash".
+func ParseHeaderField(name string) (HeaderField, error) {
+	for i, n := range fieldNames {
+		if n == name {
+			return HeaderField(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q", ErrUnknownField, name)
+}
+
+func isTypedNil(o interface{}) bool {
+	rv := reflect.ValueOf(o)
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Map, reflect.Ptr, reflect.Slice:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+func isEmpty(o interface{}) bool {
+	rv := reflect.ValueOf(o)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Chan, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	default:
+		return false
+	}
+}
+
+// CdcEncode wraps a string, int64 or HexBytes header field in the matching
+// gogoproto wrapper type and marshals it, as types.Header.Hash does. Empty
+// values encode to nil.
+func CdcEncode(item interface{}) []byte {
+	if item != nil && !isTypedNil(item) && !isEmpty(item) {
+		switch item := item.(type) {
+		case string:
+			i := gogotypes.StringValue{
+				Value: item,
+			}
+			bz, err := i.Marshal()
+			if err != nil {
+				return nil
+			}
+			return bz
+		case int64:
+			i := gogotypes.Int64Value{
+				Value: item,
+			}
+			bz, err := i.Marshal()
+			if err != nil {
+				return nil
+			}
+			return bz
+		case bytes.HexBytes:
+			i := gogotypes.BytesValue{
+				Value: item,
+			}
+			bz, err := i.Marshal()
+			if err != nil {
+				return nil
+			}
+			return bz
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// FieldValue returns the value of field in h, with the Go type EncodeField
+// expects for it.
+func FieldValue(h *types.Header, field HeaderField) (interface{}, error) {
+	if h == nil {
+		return nil, fmt.Errorf("%w: nil header", ErrInvalidHeader)
+	}
+
+	switch field {
+	case FieldVersion:
+		return h.Version, nil
+	case FieldChainID:
+		return h.ChainID, nil
+	case FieldHeight:
+		return h.Height, nil
+	case FieldTime:
+		return h.Time, nil
+	case FieldLastBlockID:
+		return h.LastBlockID, nil
+	case FieldLastCommitHash:
+		return h.LastCommitHash, nil
+	case FieldDataHash:
+		return h.DataHash, nil
+	case FieldValidatorsHash:
+		return h.ValidatorsHash, nil
+	case FieldNextValidatorsHash:
+		return h.NextValidatorsHash, nil
+	case FieldConsensusHash:
+		return h.ConsensusHash, nil
+	case FieldAppHash:
+		return h.AppHash, nil
+	case FieldLastResultsHash:
+		return h.LastResultsHash, nil
+	case FieldEvidenceHash:
+		return h.EvidenceHash, nil
+	case FieldProposerAddress:
+		return h.ProposerAddress, nil
+	default:
+		return nil, fmt.Errorf("%w %d", ErrUnknownField, int(field))
+	}
+}
+
+// EncodeField encodes value the way types.Header.Hash encodes field:
+// version.Consensus and types.BlockID as protobuf, time.Time as a protobuf
+// Timestamp, and the remaining fields through CdcEncode. Hash and address
+// fields accept either bytes.HexBytes or []byte.
+func EncodeField(field HeaderField, value interface{}) ([]byte, error) {
+	switch field {
+	case FieldVersion:
+		v, ok := value.(version.Consensus)
+		if !ok {
+			return nil, fieldTypeError(field, value)
+		}
+		vpb := v.ToProto()
+		return vpb.Marshal()
+	case FieldChainID:
+		v, ok := value.(string)
+		if !ok {
+			return nil, fieldTypeError(field, value)
+		}
+		return CdcEncode(v), nil
+	case FieldHeight:
+		v, ok := value.(int64)
+		if !ok {
+			return nil, fieldTypeError(field, value)
+		}
+		return CdcEncode(v), nil
+	case FieldTime:
+		v, ok := value.(time.Time)
+		if !ok {
+			return nil, fieldTypeError(field, value)
+		}
+		return gogotypes.StdTimeMarshal(v)
+	case FieldLastBlockID:
+		v, ok := value.(types.BlockID)
+		if !ok {
+			return nil, fieldTypeError(field, value)
+		}
+		pbbi := v.ToProto()
+		return pbbi.Marshal()
+	case FieldLastCommitHash, FieldDataHash, FieldValidatorsHash, FieldNextValidatorsHash,
+		FieldConsensusHash, FieldAppHash, FieldLastResultsHash, FieldEvidenceHash, FieldProposerAddress:
+		switch v := value.(type) {
+		case bytes.HexBytes:
+			return CdcEncode(v), nil
+		case []byte:
+			return CdcEncode(bytes.HexBytes(v)), nil
+		default:
+			return nil, fieldTypeError(field, value)
+		}
+	default:
+		return nil, fmt.Errorf("%w %d", ErrUnknownField, int(field))
+	}
+}
+
+func fieldTypeError(field HeaderField, value interface{}) error {
+	return fmt.Errorf("%w: %v: unexpected type %T", ErrInvalidFieldValue, field, value)
+}
+
+// Leaves returns the encoded header fields, in the order types.Header.Hash
+// merkleizes them.
+func Leaves(h *types.Header) ([][]byte, error) {
+	if h == nil {
+		return nil, fmt.Errorf("%w: nil header", ErrInvalidHeader)
+	}
+	if len(h.ValidatorsHash) == 0 {
+		return nil, fmt.Errorf("%w: no validators hash", ErrInvalidHeader)
+	}
+
+	leaves := make([][]byte, NumHeaderFields)
+	for field := HeaderField(0); field < NumHeaderFields; field++ {
+		value, err := FieldValue(h, field)
+		if err != nil {
+			return nil, err
+		}
+		if leaves[field], err = EncodeField(field, value); err != nil {
+			return nil, fmt.Errorf("encode %v: %w", field, err)
+		}
+	}
+	return leaves, nil
+}
+
+// HeaderFieldProof returns the encoded leaf of field and its Merkle proof
+// against root, the block hash of header.
+func HeaderFieldProof(header *types.Header, field HeaderField) (leaf []byte, proof *merkle.Proof, root []byte, err error) {
+	if !field.Valid() {
+		return nil, nil, nil, fmt.Errorf("%w %d", ErrUnknownField, int(field))
+	}
+
+	leaves, err := Leaves(header)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	root, proofs := merkle.ProofsFromByteSlices(leaves)
+	return leaves[field], proofs[field], root, nil
+}
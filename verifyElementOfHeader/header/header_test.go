@@ -0,0 +1,176 @@
+package header
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
+)
+
+// testHeader returns a header at height with every hash field set.
+func testHeader(height int64) *types.Header {
+	hash := func(s string) []byte { return tmhash.Sum([]byte(s)) }
+	return &types.Header{
+		Version:            version.Consensus{Block: version.BlockProtocol},
+		ChainID:            "test-chain",
+		Height:             height,
+		Time:               time.Date(2026, 1, 1, 0, 0, int(height), 0, time.UTC),
+		LastBlockID:        types.BlockID{Hash: hash("last"), PartSetHeader: types.PartSetHeader{Total: 1, Hash: hash("parts")}},
+		LastCommitHash:     hash("commit"),
+		DataHash:           hash("data"),
+		ValidatorsHash:     hash("vals"),
+		NextValidatorsHash: hash("vals"),
+		ConsensusHash:      hash("consensus"),
+		AppHash:            hash("app"),
+		LastResultsHash:    hash("results"),
+		EvidenceHash:       hash("evidence"),
+		ProposerAddress:    hash("proposer")[:20],
+	}
+}
+
+func TestHeaderFieldProof(t *testing.T) {
+	h := testHeader(10)
+	blockHash := h.Hash()
+
+	for field := HeaderField(0); field < NumHeaderFields; field++ {
+		t.Run(field.String(), func(t *testing.T) {
+			_, proof, root, err := HeaderFieldProof(h, field)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(root, blockHash) {
+				t.Fatalf("root %X, want block hash %X", root, blockHash)
+			}
+			value, err := FieldValue(h, field)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyHeaderField(blockHash, field, value, proof); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestVerifyHeaderFieldErrors(t *testing.T) {
+	h := testHeader(10)
+	blockHash := h.Hash()
+	_, proof, _, err := HeaderFieldProof(h, FieldAppHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		field HeaderField
+		value interface{}
+		proof bool
+		want  error
+	}{
+		{"other value", FieldAppHash, tmhash.Sum([]byte("other")), true, ErrInvalidProof},
+		{"proof of another field", FieldDataHash, h.DataHash, true, ErrInvalidProof},
+		{"nil proof", FieldAppHash, h.AppHash, false, ErrInvalidProof},
+		{"unknown field", NumHeaderFields, h.AppHash, true, ErrUnknownField},
+		{"value of the wrong type", FieldAppHash, "app", true, ErrInvalidFieldValue},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := proof
+			if !tc.proof {
+				p = nil
+			}
+			if err := VerifyHeaderField(blockHash, tc.field, tc.value, p); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestInvalidHeader(t *testing.T) {
+	if _, _, _, err := HeaderFieldProof(nil, FieldAppHash); !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("nil header: got %v, want %v", err, ErrInvalidHeader)
+	}
+	noVals := testHeader(10)
+	noVals.ValidatorsHash = nil
+	if _, _, _, err := HeaderFieldProof(noVals, FieldAppHash); !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("no validators hash: got %v, want %v", err, ErrInvalidHeader)
+	}
+	if _, err := ParseHeaderField("no_such_field"); !errors.Is(err, ErrUnknownField) {
+		t.Errorf("unknown field name: got %v, want %v", err, ErrUnknownField)
+	}
+}
+
+func TestDecodeFieldValue(t *testing.T) {
+	h := testHeader(10)
+	_, proof, _, err := HeaderFieldProof(h, FieldHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// heights are strings in RPC responses, but numbers are accepted
+	for _, raw := range []string{`"10"`, `10`} {
+		value, err := DecodeFieldValue(FieldHeight, json.RawMessage(raw))
+		if err != nil {
+			t.Fatalf("decode %s: %v", raw, err)
+		}
+		if err := VerifyHeaderField(h.Hash(), FieldHeight, value, proof); err != nil {
+			t.Errorf("verify %s: %v", raw, err)
+		}
+	}
+	if _, err := DecodeFieldValue(FieldAppHash, json.RawMessage(`"not hex"`)); err == nil {
+		t.Error("decoded a hash that is not hex")
+	}
+}
+
+func TestVerifyHeaderChain(t *testing.T) {
+	headers := make([]*types.Header, 3)
+	for i := range headers {
+		headers[i] = testHeader(int64(10 + i))
+		if i > 0 {
+			headers[i].LastBlockID.Hash = headers[i-1].Hash()
+		}
+	}
+	if i, err := VerifyHeaderChain(headers); err != nil {
+		t.Fatalf("header %d: %v", i, err)
+	}
+
+	gap := *headers[2]
+	gap.Height++
+	otherChain := *headers[2]
+	otherChain.ChainID = "other-chain"
+	sameTime := *headers[2]
+	sameTime.Time = headers[1].Time
+	otherParent := *headers[2]
+	otherParent.LastBlockID.Hash = tmhash.Sum([]byte("other"))
+	otherVals := *headers[2]
+	otherVals.ValidatorsHash = tmhash.Sum([]byte("other"))
+
+	tests := []struct {
+		name string
+		next *types.Header
+		want error
+	}{
+		{"height gap", &gap, ErrNotAdjacent},
+		{"other chain", &otherChain, ErrNotAdjacent},
+		{"same time", &sameTime, ErrNotAdjacent},
+		{"other parent", &otherParent, ErrNotAdjacent},
+		{"other validators", &otherVals, ErrNotAdjacent},
+		{"nil header", nil, ErrInvalidHeader},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			i, err := VerifyHeaderChain([]*types.Header{headers[0], headers[1], tc.next})
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+			if i != 2 {
+				t.Errorf("failed at header %d, want 2", i)
+			}
+		})
+	}
+}
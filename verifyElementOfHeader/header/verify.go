@@ -0,0 +1,87 @@
+package header
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto/merkle"
+	"github.com/tendermint/tendermint/libs/bytes"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	"github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
+)
+
+// VerifyHeaderField checks that value is the value of field in the header
+// whose hash is blockHash. The value is encoded with EncodeField, and proof
+// must be the proof for field's leaf index.
+func VerifyHeaderField(blockHash []byte, field HeaderField, value interface{}, proof *merkle.Proof) error {
+	if proof == nil {
+		return fmt.Errorf("%w: nil proof", ErrInvalidProof)
+	}
+	if !field.Valid() {
+		return fmt.Errorf("%w %d", ErrUnknownField, int(field))
+	}
+	if proof.Total != int64(NumHeaderFields) {
+		return fmt.Errorf("%w: proof is for a tree of %d leaves, a header has %d", ErrInvalidProof, proof.Total, NumHeaderFields)
+	}
+	if proof.Index != int64(field) {
+		return fmt.Errorf("%w: proof is for leaf %d, %v is leaf %d", ErrInvalidProof, proof.Index, field, int(field))
+	}
+
+	leaf, err := EncodeField(field, value)
+	if err != nil {
+		return err
+	}
+	if err := proof.Verify(blockHash, leaf); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	return nil
+}
+
+// DecodeFieldValue decodes the tendermint RPC JSON encoding of a field value
+// (as found in a /header response) into the Go type EncodeField expects.
+// Heights may be given as a JSON string or number.
+func DecodeFieldValue(field HeaderField, raw json.RawMessage) (interface{}, error) {
+	var (
+		value interface{}
+		err   error
+	)
+
+	switch field {
+	case FieldVersion:
+		var v version.Consensus
+		err = tmjson.Unmarshal(raw, &v)
+		value = v
+	case FieldChainID:
+		var v string
+		err = json.Unmarshal(raw, &v)
+		value = v
+	case FieldHeight:
+		var v int64
+		if err = tmjson.Unmarshal(raw, &v); err != nil {
+			err = json.Unmarshal(raw, &v)
+		}
+		value = v
+	case FieldTime:
+		var v time.Time
+		err = json.Unmarshal(raw, &v)
+		value = v
+	case FieldLastBlockID:
+		var v types.BlockID
+		err = tmjson.Unmarshal(raw, &v)
+		value = v
+	case FieldLastCommitHash, FieldDataHash, FieldValidatorsHash, FieldNextValidatorsHash,
+		FieldConsensusHash, FieldAppHash, FieldLastResultsHash, FieldEvidenceHash, FieldProposerAddress:
+		var v bytes.HexBytes
+		err = json.Unmarshal(raw, &v)
+		value = v
+	default:
+		return nil, fmt.Errorf("%w %d", ErrUnknownField, int(field))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("decode %v: %w", field, err)
+	}
+	return value, nil
+}
@@ -6,7 +6,7 @@ import (
 
 	"encoding/hex"
 	"fmt"
-	"reflect"
+	"log"
 	"time"
 
 	gogotypes "github.com/gogo/protobuf/types"
@@ -18,69 +18,9 @@ import (
 	tmversion "github.com/tendermint/tendermint/proto/tendermint/version"
 	"github.com/tendermint/tendermint/types"
 	"github.com/tendermint/tendermint/version"
-)
-
-func First[T, U any](val T, _ U) T {
-	return val
-}
-
-func isTypedNil(o interface{}) bool {
-	rv := reflect.ValueOf(o)
-	switch rv.Kind() {
-	case reflect.Chan, reflect.Func, reflect.Map, reflect.Ptr, reflect.Slice:
-		return rv.IsNil()
-	default:
-		return false
-	}
-}
-
-func isEmpty(o interface{}) bool {
-	rv := reflect.ValueOf(o)
-	switch rv.Kind() {
-	case reflect.Array, reflect.Chan, reflect.Map, reflect.Slice, reflect.String:
-		return rv.Len() == 0
-	default:
-		return false
-	}
-}
-
-func cdcEncode(item interface{}) []byte {
-	if item != nil && !isTypedNil(item) && !isEmpty(item) {
-		switch item := item.(type) {
-		case string:
-			i := gogotypes.StringValue{
-				Value: item,
-			}
-			bz, err := i.Marshal()
-			if err != nil {
-				return nil
-			}
-			return bz
-		case int64:
-			i := gogotypes.Int64Value{
-				Value: item,
-			}
-			bz, err := i.Marshal()
-			if err != nil {
-				return nil
-			}
-			return bz
-		case bytes.HexBytes:
-			i := gogotypes.BytesValue{
-				Value: item,
-			}
-			bz, err := i.Marshal()
-			if err != nil {
-				return nil
-			}
-			return bz
-		default:
-			return nil
-		}
-	}
 
-	return nil
-}
+	"server/headerTest/header"
+)
 
 func makeBlockID(hash []byte, partSetSize uint32, partSetHash []byte) types.BlockID {
 	var (
@@ -98,12 +38,12 @@ func makeBlockID(hash []byte, partSetSize uint32, partSetHash []byte) types.Bloc
 	}
 }
 
-func hexBytesFromString(s string) bytes.HexBytes {
+func hexBytesFromString(s string) (bytes.HexBytes, error) {
 	b, err := hex.DecodeString(s)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("decode hex %q: %w", s, err)
 	}
-	return bytes.HexBytes(b)
+	return bytes.HexBytes(b), nil
 }
 
 // Hash returns the hash of the header.
@@ -151,13 +91,18 @@ func hexBytesFromString(s string) bytes.HexBytes {
 // }
 
 func main() {
+	blockTime, err := time.Parse(time.RFC3339, "2023-02-18T17:07:42.760101663Z")
+	if err != nil {
+		log.Fatalf("parse block time: %v", err)
+	}
+
 	blockHeader := &types.Header{
 		Version: version.Consensus{
 			Block: 11,
 		},
 		ChainID: "Oraichain",
 		Height:  10340037,
-		Time:    First(time.Parse(time.RFC3339, "2023-02-18T17:07:42.760101663Z")),
+		Time:    blockTime,
 		LastBlockID: types.BlockID{
 			Hash: tmbytes.HexBytes("73CDB4036015959B90C1E0D422CCA5BAC94E74FBD92A3BB435FBE35781B33917"),
 			PartSetHeader: types.PartSetHeader{
@@ -186,8 +131,7 @@ func main() {
 		fmt.Println("error: ", err)
 	}
 
-	time := First(time.Parse(time.RFC3339, "2023-02-18T17:07:42.760101663Z"))
-	timeBytes, err := gogotypes.StdTimeMarshal(time)
+	timeBytes, err := gogotypes.StdTimeMarshal(blockTime)
 	if err != nil {
 		fmt.Println("error: ", err)
 	}
@@ -207,19 +151,19 @@ func main() {
 
 	newHeader := [][]byte{
 		versionBytes,
-		cdcEncode("Oraichain"),
-		cdcEncode(int64(10340037)),
+		header.CdcEncode("Oraichain"),
+		header.CdcEncode(int64(10340037)),
 		timeBytes,
 		blockBytes,
-		cdcEncode(tmbytes.HexBytes("14BDEB8BA16902C0CA1035D592ED964FBF73DD8F33CEF21288E786ADB7C5A0F8")),
-		cdcEncode(tmbytes.HexBytes("677BF175DE9C1EDDD2F26AE4161631390A24486D44BBC71982C39965F58967C4")),
-		cdcEncode(tmbytes.HexBytes("1A695B879702E2CBA64500C4717D9A96C951ED2083124F1179B7E7223825EA6D")),
-		cdcEncode(tmbytes.HexBytes("1A695B879702E2CBA64500C4717D9A96C951ED2083124F1179B7E7223825EA6D")),
-		cdcEncode(tmbytes.HexBytes("048091BC7DDC283F77BFBF91D73C44DA58C3DF8A9CBC867405D8B7F3DAADA22F")),
-		cdcEncode(tmbytes.HexBytes("E2BA58BAE0A12D24920774237D0B2FB97CC4678369FB5CAB90FBCAB79F33F244")),
-		cdcEncode(tmbytes.HexBytes("E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855")),
-		cdcEncode(tmbytes.HexBytes("E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855")),
-		cdcEncode(tmbytes.HexBytes("0BDC699EF20C95A99B746A8F7F18D35E2AAF0C3D")),
+		header.CdcEncode(tmbytes.HexBytes("14BDEB8BA16902C0CA1035D592ED964FBF73DD8F33CEF21288E786ADB7C5A0F8")),
+		header.CdcEncode(tmbytes.HexBytes("677BF175DE9C1EDDD2F26AE4161631390A24486D44BBC71982C39965F58967C4")),
+		header.CdcEncode(tmbytes.HexBytes("1A695B879702E2CBA64500C4717D9A96C951ED2083124F1179B7E7223825EA6D")),
+		header.CdcEncode(tmbytes.HexBytes("1A695B879702E2CBA64500C4717D9A96C951ED2083124F1179B7E7223825EA6D")),
+		header.CdcEncode(tmbytes.HexBytes("048091BC7DDC283F77BFBF91D73C44DA58C3DF8A9CBC867405D8B7F3DAADA22F")),
+		header.CdcEncode(tmbytes.HexBytes("E2BA58BAE0A12D24920774237D0B2FB97CC4678369FB5CAB90FBCAB79F33F244")),
+		header.CdcEncode(tmbytes.HexBytes("E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855")),
+		header.CdcEncode(tmbytes.HexBytes("E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855")),
+		header.CdcEncode(tmbytes.HexBytes("0BDC699EF20C95A99B746A8F7F18D35E2AAF0C3D")),
 	}
 
 	// fmt.Println("newHeader: ", newHeader)
@@ -230,7 +174,7 @@ func main() {
 
 	fmt.Println("Aunts: ", proofs[6])
 
-	fmt.Println("Leaf: ", cdcEncode(tmbytes.HexBytes("677BF175DE9C1EDDD2F26AE4161631390A24486D44BBC71982C39965F58967C4")))
+	fmt.Println("Leaf: ", header.CdcEncode(tmbytes.HexBytes("677BF175DE9C1EDDD2F26AE4161631390A24486D44BBC71982C39965F58967C4")))
 
 	fmt.Printf("LeafHash: %x\n", proofs[6].LeafHash)
 
@@ -238,7 +182,15 @@ func main() {
 
 	fmt.Println("Total: ", proofs[6].Total)
 
-	err = proofs[6].Verify(root, cdcEncode(tmbytes.HexBytes("677BF175DE9C1EDDD2F26AE4161631390A24486D44BBC71982C39965F58967C4")))
+	err = proofs[6].Verify(root, header.CdcEncode(tmbytes.HexBytes("677BF175DE9C1EDDD2F26AE4161631390A24486D44BBC71982C39965F58967C4")))
 	fmt.Println("err: ", err)
 
+	leaf, proof, hashRoot, err := header.HeaderFieldProof(blockHeader, header.FieldDataHash)
+	if err != nil {
+		fmt.Println("error: ", err)
+	}
+	fmt.Printf("%v leaf: %x\n", header.FieldDataHash, leaf)
+	fmt.Printf("%v root: %x\n", header.FieldDataHash, hashRoot)
+	fmt.Println("verify: ", proof.Verify(blockHash, leaf))
+
 }
@@ -4,6 +4,7 @@ go 1.19
 
 require (
 	github.com/cometbft/cometbft v0.37.0
+	github.com/decred/dcrd/bech32 v1.1.2
 	github.com/tendermint/tendermint v0.35.9
 )
 
@@ -3,115 +3,71 @@ package main
 import (
 	"bytes"
 	"encoding/base64"
-	"encoding/hex"
 	"fmt"
+	"log"
 
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/crypto/merkle"
-	"github.com/tendermint/tendermint/crypto/secp256k1"
-	pc "github.com/tendermint/tendermint/proto/tendermint/crypto"
-	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	"github.com/tendermint/tendermint/types"
-)
 
-func First[T, U any](val T, _ U) T {
-	return val
-}
+	"server/verifyValidator/validator"
+)
 
-// PubKeyToProto takes crypto.PubKey and transforms it to a protobuf Pubkey
-func PubKeyToProto(k crypto.PubKey) (pc.PublicKey, error) {
-	var kp pc.PublicKey
-	switch k := k.(type) {
-	case ed25519.PubKey:
-		kp = pc.PublicKey{
-			Sum: &pc.PublicKey_Ed25519{
-				Ed25519: k,
-			},
-		}
-	case secp256k1.PubKey:
-		kp = pc.PublicKey{
-			Sum: &pc.PublicKey_Secp256K1{
-				Secp256K1: k,
-			},
-		}
-	default:
-		return kp, fmt.Errorf("toproto: key type %v is not supported", k)
+// decodePubKey decodes a base64 ed25519 public key.
+func decodePubKey(pubKey string) (crypto.PubKey, error) {
+	bz, err := base64.StdEncoding.DecodeString(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode public key %q: %w", pubKey, err)
 	}
-	return kp, nil
+	return ed25519.PubKey(bz), nil
 }
 
-func Bytes(pubKey string, votingPower int64) []byte {
-	pk, err := PubKeyToProto(ed25519.PubKey(First(base64.StdEncoding.DecodeString(pubKey))))
+func Bytes(pubKey string, votingPower int64) ([]byte, error) {
+	pk, err := decodePubKey(pubKey)
 	if err != nil {
-		panic(err)
-	}
-
-	pbv := tmproto.SimpleValidator{
-		PubKey:      &pk,
-		VotingPower: votingPower,
+		return nil, err
 	}
+	return validator.SimpleValidatorBytes(pk, votingPower)
+}
 
-	bz, err := pbv.Marshal()
+func newValidator(addr string, pubKey string, votingPower, proposerPriority int64) (*types.Validator, error) {
+	pk, err := decodePubKey(pubKey)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return bz
+	return validator.NewValidator(addr, pk, votingPower, proposerPriority)
 }
 
 func main() {
+	entries := []struct {
+		addr, pubKey          string
+		votingPower, priority int64
+	}{
+		{"oraivalcons145dpmvvaj5dzm00hzrtnwyv89kz704gdk4xrl3", "jLunee++7+9tO0vVIBG59POGwkbShGiOWbtggTZMjMM=", 200, 84},
+		{"oraivalcons1r5e75xg7zu2pax6wu4q8u4ql5m8f2t3xe27c6p", "l9PY/oC7El5N7BmHIhn2Rw1n+BBSKxwPKAjnh6JCQbc=", 2, -12},
+		{"oraivalcons1rergngrr8w30gus3tsmgdxsardksgjjkzs2kgh", "w8cGC01n/3SDiUgCTq8aFQgAp5lsjlOqOIhsm/s4jOs=", 2, -12},
+		{"oraivalcons1w6jkamejxccjmu5ys63zu8n9ttwlcxv9wsms6g", "iHea1XlBnUpjaE5wDBBD9XJ+I9lQj7YUMr1wJYxiSpk=", 2, -12},
+		{"oraivalcons1339lws5730vztp7hx0kkcrfhnqfs7gws69zg6m", "Fnu5TVF9wY/Z3lHlt0rTZ6Q6NCnNToKnspzMdEGEJO8=", 2, -12},
+		{"oraivalcons15jpgu6dauyy9v9eje2nqcnx3y556w62d7jlhlu", "Og7coQxbSm4cMWbgpLqJrNPTbZi3TZBqr2CT9rPrz+E=", 2, -12},
+		{"oraivalcons1h404jv5mkcdk9fag22l0retun9pc6ryxmspf9m", "zYJafIuidhsS9dIkl0u1empVdoTKShG3LvIG18fwif4=", 2, -12},
+		{"oraivalcons1uaadxrp3hw834u78uq877p780a99qgdm5lzyqc", "0uDyc0WNK6VW98XHCYCXgyetK863YIyP31pikPp8jiU=", 2, -12},
+	}
+
+	var vals types.ValidatorSet
+	valBytes := make([][]byte, 0, len(entries))
+	for _, e := range entries {
+		val, err := newValidator(e.addr, e.pubKey, e.votingPower, e.priority)
+		if err != nil {
+			log.Fatal(err)
+		}
+		vals.Validators = append(vals.Validators, val)
 
-	vals := types.ValidatorSet{
-		Validators: []*types.Validator{
-			{
-				Address:          First(hex.DecodeString("oraivalcons145dpmvvaj5dzm00hzrtnwyv89kz704gdk4xrl3")),
-				PubKey:           ed25519.PubKey(First(base64.StdEncoding.DecodeString("jLunee++7+9tO0vVIBG59POGwkbShGiOWbtggTZMjMM="))),
-				VotingPower:      200,
-				ProposerPriority: 84,
-			},
-			{
-				Address:          First(hex.DecodeString("oraivalcons1r5e75xg7zu2pax6wu4q8u4ql5m8f2t3xe27c6p")),
-				PubKey:           ed25519.PubKey(First(base64.StdEncoding.DecodeString("l9PY/oC7El5N7BmHIhn2Rw1n+BBSKxwPKAjnh6JCQbc="))),
-				VotingPower:      2,
-				ProposerPriority: -12,
-			},
-			{
-				Address:          First(hex.DecodeString("oraivalcons1rergngrr8w30gus3tsmgdxsardksgjjkzs2kgh")),
-				PubKey:           ed25519.PubKey(First(base64.StdEncoding.DecodeString("w8cGC01n/3SDiUgCTq8aFQgAp5lsjlOqOIhsm/s4jOs="))),
-				VotingPower:      2,
-				ProposerPriority: -12,
-			},
-			{
-				Address:          First(hex.DecodeString("oraivalcons1w6jkamejxccjmu5ys63zu8n9ttwlcxv9wsms6g")),
-				PubKey:           ed25519.PubKey(First(base64.StdEncoding.DecodeString("iHea1XlBnUpjaE5wDBBD9XJ+I9lQj7YUMr1wJYxiSpk="))),
-				VotingPower:      2,
-				ProposerPriority: -12,
-			},
-			{
-				Address:          First(hex.DecodeString("oraivalcons1339lws5730vztp7hx0kkcrfhnqfs7gws69zg6m")),
-				PubKey:           ed25519.PubKey(First(base64.StdEncoding.DecodeString("Fnu5TVF9wY/Z3lHlt0rTZ6Q6NCnNToKnspzMdEGEJO8="))),
-				VotingPower:      2,
-				ProposerPriority: -12,
-			},
-			{
-				Address:          First(hex.DecodeString("oraivalcons15jpgu6dauyy9v9eje2nqcnx3y556w62d7jlhlu")),
-				PubKey:           ed25519.PubKey(First(base64.StdEncoding.DecodeString("Og7coQxbSm4cMWbgpLqJrNPTbZi3TZBqr2CT9rPrz+E="))),
-				VotingPower:      2,
-				ProposerPriority: -12,
-			},
-			{
-				Address:          First(hex.DecodeString("oraivalcons1h404jv5mkcdk9fag22l0retun9pc6ryxmspf9m")),
-				PubKey:           ed25519.PubKey(First(base64.StdEncoding.DecodeString("zYJafIuidhsS9dIkl0u1empVdoTKShG3LvIG18fwif4="))),
-				VotingPower:      2,
-				ProposerPriority: -12,
-			},
-			{
-				Address:          First(hex.DecodeString("oraivalcons1uaadxrp3hw834u78uq877p780a99qgdm5lzyqc")),
-				PubKey:           ed25519.PubKey(First(base64.StdEncoding.DecodeString("0uDyc0WNK6VW98XHCYCXgyetK863YIyP31pikPp8jiU="))),
-				VotingPower:      2,
-				ProposerPriority: -12,
-			},
-		},
+		bz, err := Bytes(e.pubKey, e.votingPower)
+		if err != nil {
+			log.Fatal(err)
+		}
+		valBytes = append(valBytes, bz)
 	}
 
 	// fmt.Printf("vals : %x", vals.Hash())
@@ -119,29 +75,6 @@ func main() {
 	root := vals.Hash()
 	fmt.Printf("expected root : %x\n", root)
 
-	// // test := tmproto.SimpleValidator{
-	// // 	PubKey:      ed25519.PubKey(First(base64.StdEncoding.DecodeString("jLunee++7+9tO0vVIBG59POGwkbShGiOWbtggTZMjMM="))),
-	// // 	VotingPower: 200,
-	// // }
-	// pubTest, er := PubKeyToProto(ed25519.PubKey(First(base64.StdEncoding.DecodeString("jLunee++7+9tO0vVIBG59POGwkbShGiOWbtggTZMjMM="))))
-
-	// if er != nil {
-	// 	fmt.Println("error")
-	// }
-
-	// fmt.Printf("pubTest : %x \n", pubTest)
-
-	valBytes := [][]byte{
-		Bytes("jLunee++7+9tO0vVIBG59POGwkbShGiOWbtggTZMjMM=", 200),
-		Bytes("l9PY/oC7El5N7BmHIhn2Rw1n+BBSKxwPKAjnh6JCQbc=", 2),
-		Bytes("w8cGC01n/3SDiUgCTq8aFQgAp5lsjlOqOIhsm/s4jOs=", 2),
-		Bytes("iHea1XlBnUpjaE5wDBBD9XJ+I9lQj7YUMr1wJYxiSpk=", 2),
-		Bytes("Fnu5TVF9wY/Z3lHlt0rTZ6Q6NCnNToKnspzMdEGEJO8=", 2),
-		Bytes("Og7coQxbSm4cMWbgpLqJrNPTbZi3TZBqr2CT9rPrz+E=", 2),
-		Bytes("zYJafIuidhsS9dIkl0u1empVdoTKShG3LvIG18fwif4=", 2),
-		Bytes("0uDyc0WNK6VW98XHCYCXgyetK863YIyP31pikPp8jiU=", 2),
-	}
-
 	newRoot, _ := merkle.ProofsFromByteSlices(valBytes)
 	fmt.Printf("newRoot : %x \n", newRoot)
 
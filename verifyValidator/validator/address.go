@@ -0,0 +1,88 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/decred/dcrd/bech32"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/types"
+)
+
+// DecodeBech32Address decodes a bech32 address with any human-readable
+// prefix, such as oraivalcons or cosmosvalcons.
+func DecodeBech32Address(addr string) (prefix string, bz []byte, err error) {
+	prefix, bz, err = bech32.DecodeToBase256(addr)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: decode bech32 address %q: %v", ErrInvalidAddress, addr, err)
+	}
+	return prefix, bz, nil
+}
+
+// EncodeBech32Address encodes bz as a bech32 address with the given prefix.
+func EncodeBech32Address(prefix string, bz []byte) (string, error) {
+	return bech32.EncodeFromBase256(prefix, bz)
+}
+
+// ParseAddress decodes a validator address given either in bech32 or as the
+// hex string tendermint RPC uses.
+func ParseAddress(addr string) (crypto.Address, error) {
+	if _, bz, err := DecodeBech32Address(addr); err == nil {
+		return bz, nil
+	}
+
+	bz, err := hex.DecodeString(addr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q is neither bech32 nor hex", ErrInvalidAddress, addr)
+	}
+	return bz, nil
+}
+
+// ConsensusAddress derives the consensus address of a validator key: the
+// first 20 bytes of SHA256 for ed25519 and sr25519, RIPEMD160(SHA256) for
+// secp256k1.
+func ConsensusAddress(pubKey crypto.PubKey) (crypto.Address, error) {
+	if err := CheckPubKey(pubKey); err != nil {
+		return nil, err
+	}
+	return pubKey.Address(), nil
+}
+
+// CheckAddress returns an error if addr is not the consensus address of
+// pubKey.
+func CheckAddress(addr crypto.Address, pubKey crypto.PubKey) error {
+	derived, err := ConsensusAddress(pubKey)
+	if err != nil {
+		return fmt.Errorf("address %v: %w", addr, err)
+	}
+	if !bytes.Equal(addr, derived) {
+		return fmt.Errorf("%w: address %v is not %s key address %v", ErrAddressMismatch, addr, pubKey.Type(), derived)
+	}
+	return nil
+}
+
+// NewValidator builds a validator from an address in bech32 or hex, checking
+// it against pubKey. An empty address is derived from pubKey.
+func NewValidator(addr string, pubKey crypto.PubKey, votingPower, proposerPriority int64) (*types.Validator, error) {
+	address, err := ConsensusAddress(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("validator %q: %w", addr, err)
+	}
+	if addr != "" {
+		parsed, err := ParseAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		if err := CheckAddress(parsed, pubKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return &types.Validator{
+		Address:          address,
+		PubKey:           pubKey,
+		VotingPower:      votingPower,
+		ProposerPriority: proposerPriority,
+	}, nil
+}
@@ -0,0 +1,24 @@
+package validator
+
+import "errors"
+
+// Errors returned, wrapped, by this package, so callers can classify them
+// with errors.Is.
+var (
+	// ErrInvalidValidatorSet is a validator set that is empty, holds a nil
+	// validator or one without a key, or whose voting power is out of
+	// range.
+	ErrInvalidValidatorSet = errors.New("invalid validator set")
+	// ErrUnsupportedKeyType is a public key of a type other than ed25519,
+	// secp256k1 or sr25519, or of the wrong length.
+	ErrUnsupportedKeyType = errors.New("unsupported key type")
+	// ErrInvalidAddress is an address that is neither bech32 nor hex.
+	ErrInvalidAddress = errors.New("invalid address")
+	// ErrAddressMismatch is an address that is not the consensus address
+	// of the validator's key.
+	ErrAddressMismatch = errors.New("address does not match public key")
+	// ErrNotInSet is a validator missing from the validator set.
+	ErrNotInSet = errors.New("validator not in set")
+	// ErrInvalidProof is a validator inclusion proof that does not verify.
+	ErrInvalidProof = errors.New("invalid inclusion proof")
+)
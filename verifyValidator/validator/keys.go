@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	"github.com/tendermint/tendermint/crypto/sr25519"
+)
+
+// pubKeySizes are the raw public key lengths of the supported key types.
+var pubKeySizes = map[string]int{
+	ed25519.KeyType:   ed25519.PubKeySize,
+	secp256k1.KeyType: secp256k1.PubKeySize,
+	sr25519.KeyType:   sr25519.PubKeySize,
+}
+
+// CheckPubKey returns an error if pubKey is not an ed25519, secp256k1 or
+// sr25519 key of the right length. Keys that pass can be hashed, addressed
+// and used to verify signatures without panicking.
+func CheckPubKey(pubKey crypto.PubKey) error {
+	if pubKey == nil {
+		return fmt.Errorf("%w: nil public key", ErrUnsupportedKeyType)
+	}
+	size, ok := pubKeySizes[pubKey.Type()]
+	if !ok {
+		return fmt.Errorf("%w: key type %q is not supported", ErrUnsupportedKeyType, pubKey.Type())
+	}
+	if n := len(pubKey.Bytes()); n != size {
+		return fmt.Errorf("%w: invalid %s public key length %d", ErrUnsupportedKeyType, pubKey.Type(), n)
+	}
+	return nil
+}
+
+// PubKeyFromBytes builds a public key of the given type from its raw bytes:
+// 32 bytes for ed25519 and sr25519, the 33-byte compressed point for
+// secp256k1. An empty keyType defaults to ed25519.
+func PubKeyFromBytes(keyType string, bz []byte) (crypto.PubKey, error) {
+	var pubKey crypto.PubKey
+	switch keyType {
+	case "", ed25519.KeyType:
+		pubKey = ed25519.PubKey(bz)
+	case secp256k1.KeyType:
+		pubKey = secp256k1.PubKey(bz)
+	case sr25519.KeyType:
+		pubKey = sr25519.PubKey(bz)
+	default:
+		return nil, fmt.Errorf("%w: key type %q is not supported", ErrUnsupportedKeyType, keyType)
+	}
+
+	if err := CheckPubKey(pubKey); err != nil {
+		return nil, err
+	}
+	return pubKey, nil
+}
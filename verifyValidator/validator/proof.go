@@ -0,0 +1,95 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/types"
+)
+
+// InclusionProof proves that a validator with the given key and voting power
+// is a member of the set committed to by a ValidatorsHash.
+type InclusionProof struct {
+	Index       int              `json:"index"`
+	Address     tmbytes.HexBytes `json:"address"`
+	VotingPower int64            `json:"voting_power"`
+	Leaf        []byte           `json:"leaf"`
+	Proof       *merkle.Proof    `json:"proof"`
+}
+
+// HashWithProofs returns the ValidatorsHash of vals and the inclusion proof of
+// every validator, in order. A validator address that is not the consensus
+// address of its key is rejected; an empty one is derived from the key.
+func HashWithProofs(vals []*types.Validator) ([]byte, []InclusionProof, error) {
+	if len(vals) == 0 {
+		return nil, nil, fmt.Errorf("%w: no validators", ErrInvalidValidatorSet)
+	}
+
+	leaves, err := Leaves(vals)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root, proofs := merkle.ProofsFromByteSlices(leaves)
+	res := make([]InclusionProof, len(vals))
+	for i, val := range vals {
+		address := val.Address
+		if len(address) == 0 {
+			// the leaf encoding above already checked the key
+			address, _ = ConsensusAddress(val.PubKey)
+		} else if err := CheckAddress(address, val.PubKey); err != nil {
+			return nil, nil, fmt.Errorf("validator #%d: %w", i, err)
+		}
+		res[i] = InclusionProof{
+			Index:       i,
+			Address:     tmbytes.HexBytes(address),
+			VotingPower: val.VotingPower,
+			Leaf:        leaves[i],
+			Proof:       proofs[i],
+		}
+	}
+	return root, res, nil
+}
+
+// ProveValidator returns the ValidatorsHash of vals and the inclusion proof of
+// the validator with the given address.
+func ProveValidator(vals []*types.Validator, address crypto.Address) ([]byte, *InclusionProof, error) {
+	root, proofs, err := HashWithProofs(vals)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof, err := FindProof(proofs, address)
+	if err != nil {
+		return nil, nil, err
+	}
+	return root, proof, nil
+}
+
+// FindProof returns the proof of the validator with the given address.
+func FindProof(proofs []InclusionProof, address crypto.Address) (*InclusionProof, error) {
+	for i := range proofs {
+		if bytes.Equal(proofs[i].Address, address) {
+			return &proofs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %v", ErrNotInSet, address)
+}
+
+// VerifyInclusion checks that proof proves a validator with pubKey and
+// votingPower is a member of the set hashing to validatorsHash.
+func VerifyInclusion(validatorsHash []byte, pubKey crypto.PubKey, votingPower int64, proof *merkle.Proof) error {
+	if proof == nil {
+		return fmt.Errorf("%w: nil proof", ErrInvalidProof)
+	}
+	leaf, err := SimpleValidatorBytes(pubKey, votingPower)
+	if err != nil {
+		return err
+	}
+	if err := proof.Verify(validatorsHash, leaf); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	return nil
+}
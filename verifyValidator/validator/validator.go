@@ -0,0 +1,141 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	"github.com/tendermint/tendermint/crypto/sr25519"
+	pc "github.com/tendermint/tendermint/proto/tendermint/crypto"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// PubKeyToProto takes crypto.PubKey and transforms it to a protobuf Pubkey
+func PubKeyToProto(k crypto.PubKey) (pc.PublicKey, error) {
+	var kp pc.PublicKey
+	if err := CheckPubKey(k); err != nil {
+		return kp, fmt.Errorf("toproto: %w", err)
+	}
+	switch k := k.(type) {
+	case ed25519.PubKey:
+		kp = pc.PublicKey{
+			Sum: &pc.PublicKey_Ed25519{
+				Ed25519: k,
+			},
+		}
+	case secp256k1.PubKey:
+		kp = pc.PublicKey{
+			Sum: &pc.PublicKey_Secp256K1{
+				Secp256K1: k,
+			},
+		}
+	case sr25519.PubKey:
+		kp = pc.PublicKey{
+			Sum: &pc.PublicKey_Sr25519{
+				Sr25519: k,
+			},
+		}
+	default:
+		return kp, fmt.Errorf("toproto: %w: key type %v is not supported", ErrUnsupportedKeyType, k)
+	}
+	return kp, nil
+}
+
+// SimpleValidatorBytes returns the SimpleValidator encoding of a validator,
+// which is the leaf it occupies in the ValidatorsHash Merkle tree.
+func SimpleValidatorBytes(pubKey crypto.PubKey, votingPower int64) ([]byte, error) {
+	pk, err := PubKeyToProto(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	pbv := tmproto.SimpleValidator{
+		PubKey:      &pk,
+		VotingPower: votingPower,
+	}
+
+	return pbv.Marshal()
+}
+
+// Leaves returns the SimpleValidator encodings of vals, in order.
+func Leaves(vals []*types.Validator) ([][]byte, error) {
+	leaves := make([][]byte, len(vals))
+	for i, val := range vals {
+		if val == nil {
+			return nil, fmt.Errorf("%w: validator #%d is nil", ErrInvalidValidatorSet, i)
+		}
+		bz, err := SimpleValidatorBytes(val.PubKey, val.VotingPower)
+		if err != nil {
+			return nil, fmt.Errorf("validator #%d: %w", i, err)
+		}
+		leaves[i] = bz
+	}
+	return leaves, nil
+}
+
+// CheckSet checks that vals can be used as a validator set without
+// tendermint panicking: that it is not empty, that every validator has a key
+// and a non-negative voting power, and that the total voting power does not
+// exceed types.MaxTotalVotingPower. It also checks that every address is the
+// consensus address of the validator's key, since the ValidatorsHash does
+// not commit to addresses.
+func CheckSet(vals []*types.Validator) error {
+	if len(vals) == 0 {
+		return fmt.Errorf("%w: no validators", ErrInvalidValidatorSet)
+	}
+	var total int64
+	for i, val := range vals {
+		switch {
+		case val == nil:
+			return fmt.Errorf("%w: validator #%d is nil", ErrInvalidValidatorSet, i)
+		case val.PubKey == nil:
+			return fmt.Errorf("%w: validator #%d has no public key", ErrInvalidValidatorSet, i)
+		case val.VotingPower < 0:
+			return fmt.Errorf("%w: validator #%d has negative voting power %d", ErrInvalidValidatorSet, i, val.VotingPower)
+		case val.VotingPower > types.MaxTotalVotingPower-total:
+			return fmt.Errorf("%w: total voting power exceeds %d", ErrInvalidValidatorSet, types.MaxTotalVotingPower)
+		}
+		if err := CheckAddress(val.Address, val.PubKey); err != nil {
+			return fmt.Errorf("validator #%d: %w", i, err)
+		}
+		total += val.VotingPower
+	}
+	return nil
+}
+
+// SortByVotingPower returns a copy of vals in the order of a tendermint
+// validator set, which its ValidatorsHash depends on: by voting power,
+// descending, then by address. An empty address is derived from the key.
+func SortByVotingPower(vals []*types.Validator) ([]*types.Validator, error) {
+	sorted := make([]*types.Validator, len(vals))
+	for i, val := range vals {
+		if val == nil {
+			return nil, fmt.Errorf("%w: validator #%d is nil", ErrInvalidValidatorSet, i)
+		}
+		val = val.Copy()
+		if len(val.Address) == 0 {
+			address, err := ConsensusAddress(val.PubKey)
+			if err != nil {
+				return nil, fmt.Errorf("validator #%d: %w", i, err)
+			}
+			val.Address = address
+		}
+		sorted[i] = val
+	}
+	sort.Sort(types.ValidatorsByVotingPower(sorted))
+	return sorted, nil
+}
+
+// Hash returns the ValidatorsHash of vals: the Merkle root of their
+// SimpleValidator encodings.
+func Hash(vals []*types.Validator) ([]byte, error) {
+	leaves, err := Leaves(vals)
+	if err != nil {
+		return nil, err
+	}
+	return merkle.HashFromByteSlices(leaves), nil
+}
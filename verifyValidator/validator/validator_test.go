@@ -0,0 +1,73 @@
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestCheckSet(t *testing.T) {
+	newVal := func(power int64) *types.Validator {
+		return types.NewValidator(ed25519.GenPrivKey().PubKey(), power)
+	}
+	// a validator claiming the address of another's key
+	forged := newVal(10)
+	forged.Address = newVal(10).Address
+	noKey := newVal(10)
+	noKey.PubKey = nil
+
+	tests := []struct {
+		name string
+		vals []*types.Validator
+		want error
+	}{
+		{"valid", []*types.Validator{newVal(10), newVal(20)}, nil},
+		{"empty", nil, ErrInvalidValidatorSet},
+		{"nil validator", []*types.Validator{newVal(10), nil}, ErrInvalidValidatorSet},
+		{"no key", []*types.Validator{noKey}, ErrInvalidValidatorSet},
+		{"negative power", []*types.Validator{newVal(-1)}, ErrInvalidValidatorSet},
+		{"power overflow", []*types.Validator{newVal(types.MaxTotalVotingPower), newVal(1)}, ErrInvalidValidatorSet},
+		{"address of another key", []*types.Validator{newVal(10), forged}, ErrAddressMismatch},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckSet(tc.vals)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestSortByVotingPower(t *testing.T) {
+	vals := make([]*types.Validator, 6)
+	for i := range vals {
+		// two validators of each power, so ties are ordered by address
+		vals[i] = types.NewValidator(ed25519.GenPrivKey().PubKey(), int64(10*(1+i/2)))
+	}
+	want := types.NewValidatorSet(vals).Hash()
+
+	shuffled := []*types.Validator{vals[3], vals[0], vals[5], vals[1], vals[4], vals[2]}
+	// an empty address is derived before sorting
+	noAddress := shuffled[1].Copy()
+	noAddress.Address = nil
+	shuffled[1] = noAddress
+
+	sorted, err := SortByVotingPower(shuffled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Hash(sorted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("hash %X of the sorted set, want %X", got, want)
+	}
+	if shuffled[1].Address != nil {
+		t.Error("input validator modified")
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+const (
+	requestQueue  = "go_service_req"
+	responseQueue = "go_service_res"
+
+	// minBackoff and maxBackoff bound the wait between reconnection
+	// attempts, which doubles after each failed one.
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+)
+
+// worker consumes requests from RabbitMQ and publishes the replies. When the
+// connection or the channel closes, it reconnects with backoff, declaring the
+// queue and registering the consumer again.
+type worker struct {
+	URL string
+	// MaxDowntime is how long the worker tries to reconnect before Run
+	// gives up.
+	MaxDowntime time.Duration
+
+	healthy atomic.Bool
+}
+
+// Healthy reports whether the worker is connected and consuming.
+func (w *worker) Healthy() bool {
+	return w.healthy.Load()
+}
+
+// Run serves requests until ctx is done, reconnecting whenever the session
+// ends. It returns an error if it cannot reconnect within MaxDowntime.
+func (w *worker) Run(ctx context.Context) error {
+	var downSince time.Time
+	backoff := minBackoff
+	for {
+		connected, err := w.serve(ctx)
+		w.healthy.Store(false)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// the downtime starts when a session is lost, not at each attempt
+		if connected || downSince.IsZero() {
+			downSince = time.Now()
+			backoff = minBackoff
+		}
+		down := time.Since(downSince)
+		if down >= w.MaxDowntime {
+			return fmt.Errorf("RabbitMQ unavailable for %v: %w", down.Round(time.Second), err)
+		}
+		// make a last attempt at the deadline rather than sleep past it
+		wait := backoff
+		if left := w.MaxDowntime - down; wait > left {
+			wait = left
+		}
+
+		log.Printf("RabbitMQ session ended: %v; reconnecting in %v", err, wait.Round(time.Millisecond))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// serve runs one session: it connects, declares the request queue and
+// consumes it until ctx is done or the connection, the channel or the
+// consumer goes away. connected reports whether the consumer was registered.
+func (w *worker) serve(ctx context.Context) (connected bool, err error) {
+	conn, err := amqp.Dial(w.URL)
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return false, fmt.Errorf("failed to open a channel: %w", err)
+	}
+	defer ch.Close()
+
+	// buffered so the library is not blocked sending the close reason
+	connClosed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	chClosed := ch.NotifyClose(make(chan *amqp.Error, 1))
+
+	q, err := ch.QueueDeclare(
+		requestQueue, // queue name
+		false,        // durable
+		false,        // delete when unused
+		false,        // exclusive
+		false,        // no-wait
+		nil,          // arguments
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to declare a queue: %w", err)
+	}
+
+	msgs, err := ch.Consume(
+		q.Name, // queue
+		"",     // consumer
+		true,   // auto-ack
+		false,  // exclusive
+		false,  // no-local
+		false,  // no-wait
+		nil,    // args
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to register a consumer: %w", err)
+	}
+
+	w.healthy.Store(true)
+	log.Printf(" [*] Waiting for messages on %s", q.Name)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, nil
+		case amqpErr := <-connClosed:
+			return true, closeError("connection", amqpErr)
+		case amqpErr := <-chClosed:
+			return true, closeError("channel", amqpErr)
+		case d, ok := <-msgs:
+			if !ok {
+				return true, errors.New("consumer cancelled")
+			}
+			w.handle(ch, d)
+		}
+	}
+}
+
+// handle dispatches one delivery and publishes the reply on ch.
+func (w *worker) handle(ch *amqp.Channel, d amqp.Delivery) {
+	log.Printf("Received a message: %s", d.Body)
+
+	res := handleRequest(d.Body, d.CorrelationId)
+	if res.Status == StatusError {
+		log.Printf("service %q failed: %s", res.TypeService, res.ErrorMessage)
+	}
+
+	resBytes, err := json.Marshal(res)
+	if err != nil {
+		log.Printf("failed to serialize response: %v", err)
+		return
+	}
+
+	if err := ch.Publish(
+		"",            // exchange
+		responseQueue, // routing key
+		false,         // mandatory
+		false,         // immediate
+		amqp.Publishing{
+			ContentType:   "application/json",
+			CorrelationId: d.CorrelationId,
+			Body:          resBytes,
+		},
+	); err != nil {
+		log.Printf("failed to publish response: %v", err)
+	}
+}
+
+// closeError describes the close of what, whose reason is nil when it was
+// closed gracefully.
+func closeError(what string, reason *amqp.Error) error {
+	if reason == nil {
+		return fmt.Errorf("%s closed", what)
+	}
+	return fmt.Errorf("%s closed: %w", what, reason)
+}